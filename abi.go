@@ -0,0 +1,511 @@
+package oklink
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+type ABIArgument struct {
+	Name       string        `json:"name"`
+	Type       string        `json:"type"`
+	Indexed    bool          `json:"indexed"`
+	Components []ABIArgument `json:"components"`
+}
+
+type ABIMethod struct {
+	Name      string
+	Inputs    []ABIArgument
+	Outputs   []ABIArgument
+	Signature string
+	Selector  string
+}
+
+type ABIEvent struct {
+	Name      string
+	Inputs    []ABIArgument
+	Anonymous bool
+	Signature string
+	Topic     string
+}
+
+type ABI struct {
+	Methods map[string]ABIMethod
+	Events  map[string]ABIEvent
+}
+
+type DecodedArg struct {
+	Name    string
+	Type    string
+	Indexed bool
+	Value   any
+}
+
+type DecodedCall struct {
+	Selector  string
+	Name      string
+	Signature string
+	Source    DecodeSource
+	Args      []DecodedArg
+	// FetchErr is why the verified ABI could not be fetched when the call was decoded from signatures instead.
+	FetchErr error
+}
+
+type DecodedLog struct {
+	Log       Log
+	Name      string
+	Signature string
+	Args      []DecodedArg
+}
+
+type abiEntry struct {
+	Type      string        `json:"type"`
+	Name      string        `json:"name"`
+	Inputs    []ABIArgument `json:"inputs"`
+	Outputs   []ABIArgument `json:"outputs"`
+	Anonymous bool          `json:"anonymous"`
+}
+
+type abiType struct {
+	kind   string
+	size   int
+	length int
+	elem   *abiType
+	fields []abiField
+}
+
+type abiField struct {
+	name string
+	typ  *abiType
+}
+
+func ParseABI(data []byte) (*ABI, error) {
+	var entries []abiEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("error unmarshalling ABI: %w", err)
+	}
+
+	abi := &ABI{Methods: map[string]ABIMethod{}, Events: map[string]ABIEvent{}}
+	for _, entry := range entries {
+		switch entry.Type {
+		case "function", "":
+			signature, err := abiSignature(entry.Name, entry.Inputs)
+			if err != nil {
+				return nil, err
+			}
+			selector := "0x" + hex.EncodeToString(keccak256([]byte(signature))[:4])
+			abi.Methods[selector] = ABIMethod{
+				Name:      entry.Name,
+				Inputs:    entry.Inputs,
+				Outputs:   entry.Outputs,
+				Signature: signature,
+				Selector:  selector,
+			}
+		case "event":
+			signature, err := abiSignature(entry.Name, entry.Inputs)
+			if err != nil {
+				return nil, err
+			}
+			topic := "0x" + hex.EncodeToString(keccak256([]byte(signature)))
+			abi.Events[topic] = ABIEvent{
+				Name:      entry.Name,
+				Inputs:    entry.Inputs,
+				Anonymous: entry.Anonymous,
+				Signature: signature,
+				Topic:     topic,
+			}
+		}
+	}
+	return abi, nil
+}
+
+func (a *ABI) DecodeLog(log Log) (*DecodedLog, error) {
+	if len(log.Topics) == 0 {
+		return nil, errors.New("log has no topics")
+	}
+	event, ok := a.Events[strings.ToLower(log.Topics[0])]
+	if !ok {
+		return nil, fmt.Errorf("unknown event topic %s", log.Topics[0])
+	}
+
+	data, err := decodeHex(log.Data)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding log data: %w", err)
+	}
+
+	var nonIndexed []ABIArgument
+	for _, input := range event.Inputs {
+		if !input.Indexed {
+			nonIndexed = append(nonIndexed, input)
+		}
+	}
+	values, err := decodeArguments(nonIndexed, data)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding %s data: %w", event.Name, err)
+	}
+
+	decoded := &DecodedLog{Log: log, Name: event.Name, Signature: event.Signature}
+	topic := 1
+	for _, input := range event.Inputs {
+		if !input.Indexed {
+			decoded.Args = append(decoded.Args, values[0])
+			values = values[1:]
+			continue
+		}
+		if topic >= len(log.Topics) {
+			return nil, fmt.Errorf("log is missing topic for indexed argument %s", input.Name)
+		}
+		value, err := decodeTopic(input, log.Topics[topic])
+		if err != nil {
+			return nil, fmt.Errorf("error decoding %s topic %d: %w", event.Name, topic, err)
+		}
+		decoded.Args = append(decoded.Args, value)
+		topic++
+	}
+	return decoded, nil
+}
+
+func (a *ABI) DecodeLogs(logs []Log) ([]DecodedLog, error) {
+	var decoded []DecodedLog
+	for _, log := range logs {
+		if len(log.Topics) == 0 {
+			continue
+		}
+		// Logs from other contracts or unknown events are skipped rather than failing the batch.
+		if _, ok := a.Events[strings.ToLower(log.Topics[0])]; !ok {
+			continue
+		}
+		entry, err := a.DecodeLog(log)
+		if err != nil {
+			return decoded, err
+		}
+		decoded = append(decoded, *entry)
+	}
+	return decoded, nil
+}
+
+func (d DecodedLog) Arg(name string) (any, bool) {
+	for _, arg := range d.Args {
+		if arg.Name == name {
+			return arg.Value, true
+		}
+	}
+	return nil, false
+}
+
+func decodeTopic(input ABIArgument, topic string) (DecodedArg, error) {
+	typ, err := parseABIType(input.Type, input.Components)
+	if err != nil {
+		return DecodedArg{}, err
+	}
+	word, err := decodeHex(topic)
+	if err != nil {
+		return DecodedArg{}, err
+	}
+	if len(word) != 32 {
+		return DecodedArg{}, fmt.Errorf("topic is %d bytes, want 32", len(word))
+	}
+	arg := DecodedArg{Name: input.Name, Type: input.Type, Indexed: true}
+	// Dynamic indexed values are stored as their keccak256 hash and cannot be recovered.
+	if typ.dynamic() || typ.kind == "array" || typ.kind == "tuple" {
+		arg.Value = "0x" + hex.EncodeToString(word)
+		return arg, nil
+	}
+	arg.Value, err = typ.decode(word)
+	return arg, err
+}
+
+func decodeArguments(inputs []ABIArgument, data []byte) ([]DecodedArg, error) {
+	fields := make([]abiField, len(inputs))
+	for i, input := range inputs {
+		typ, err := parseABIType(input.Type, input.Components)
+		if err != nil {
+			return nil, err
+		}
+		fields[i] = abiField{name: input.Name, typ: typ}
+	}
+	values, err := decodeTuple(fields, data)
+	if err != nil {
+		return nil, err
+	}
+	args := make([]DecodedArg, len(inputs))
+	for i, input := range inputs {
+		args[i] = DecodedArg{Name: input.Name, Type: input.Type, Value: values[i]}
+	}
+	return args, nil
+}
+
+func parseABIType(typ string, components []ABIArgument) (*abiType, error) {
+	if strings.HasSuffix(typ, "]") {
+		open := strings.LastIndex(typ, "[")
+		if open < 0 {
+			return nil, fmt.Errorf("invalid ABI type %q", typ)
+		}
+		elem, err := parseABIType(typ[:open], components)
+		if err != nil {
+			return nil, err
+		}
+		size := typ[open+1 : len(typ)-1]
+		if size == "" {
+			return &abiType{kind: "slice", elem: elem}, nil
+		}
+		length, err := strconv.Atoi(size)
+		if err != nil || length <= 0 {
+			return nil, fmt.Errorf("invalid ABI array length in %q", typ)
+		}
+		return &abiType{kind: "array", length: length, elem: elem}, nil
+	}
+
+	switch {
+	case typ == "tuple":
+		fields := make([]abiField, len(components))
+		for i, component := range components {
+			fieldType, err := parseABIType(component.Type, component.Components)
+			if err != nil {
+				return nil, err
+			}
+			fields[i] = abiField{name: component.Name, typ: fieldType}
+		}
+		return &abiType{kind: "tuple", fields: fields}, nil
+	case typ == "address", typ == "bool", typ == "string", typ == "bytes":
+		return &abiType{kind: typ}, nil
+	case strings.HasPrefix(typ, "uint"), strings.HasPrefix(typ, "int"):
+		kind := "int"
+		if strings.HasPrefix(typ, "uint") {
+			kind = "uint"
+		}
+		bits := 256
+		if suffix := strings.TrimPrefix(typ, kind); suffix != "" {
+			parsed, err := strconv.Atoi(suffix)
+			if err != nil || parsed <= 0 || parsed > 256 || parsed%8 != 0 {
+				return nil, fmt.Errorf("invalid ABI integer type %q", typ)
+			}
+			bits = parsed
+		}
+		return &abiType{kind: kind, size: bits}, nil
+	case strings.HasPrefix(typ, "bytes"):
+		size, err := strconv.Atoi(strings.TrimPrefix(typ, "bytes"))
+		if err != nil || size <= 0 || size > 32 {
+			return nil, fmt.Errorf("invalid ABI bytes type %q", typ)
+		}
+		return &abiType{kind: "fixedbytes", size: size}, nil
+	}
+	return nil, fmt.Errorf("unsupported ABI type %q", typ)
+}
+
+func (t *abiType) dynamic() bool {
+	switch t.kind {
+	case "string", "bytes", "slice":
+		return true
+	case "array":
+		return t.elem.dynamic()
+	case "tuple":
+		for _, field := range t.fields {
+			if field.typ.dynamic() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (t *abiType) headSize() int {
+	if t.dynamic() {
+		return 32
+	}
+	switch t.kind {
+	case "array":
+		return t.length * t.elem.headSize()
+	case "tuple":
+		size := 0
+		for _, field := range t.fields {
+			size += field.typ.headSize()
+		}
+		return size
+	}
+	return 32
+}
+
+func (t *abiType) canonical() string {
+	switch t.kind {
+	case "uint", "int":
+		return t.kind + strconv.Itoa(t.size)
+	case "fixedbytes":
+		return "bytes" + strconv.Itoa(t.size)
+	case "slice":
+		return t.elem.canonical() + "[]"
+	case "array":
+		return t.elem.canonical() + "[" + strconv.Itoa(t.length) + "]"
+	case "tuple":
+		types := make([]string, len(t.fields))
+		for i, field := range t.fields {
+			types[i] = field.typ.canonical()
+		}
+		return "(" + strings.Join(types, ",") + ")"
+	}
+	return t.kind
+}
+
+func (t *abiType) decode(data []byte) (any, error) {
+	switch t.kind {
+	case "uint", "int", "address", "bool", "fixedbytes":
+		if len(data) < 32 {
+			return nil, errors.New("ABI data too short")
+		}
+	}
+
+	switch t.kind {
+	case "uint":
+		return new(big.Int).SetBytes(data[:32]), nil
+	case "int":
+		value := new(big.Int).SetBytes(data[:32])
+		if data[0]&0x80 != 0 {
+			value.Sub(value, new(big.Int).Lsh(big.NewInt(1), 256))
+		}
+		return value, nil
+	case "address":
+		return "0x" + hex.EncodeToString(data[12:32]), nil
+	case "bool":
+		return data[31] != 0, nil
+	case "fixedbytes":
+		return append([]byte(nil), data[:t.size]...), nil
+	case "bytes", "string":
+		length, err := readABIOffset(data, 0)
+		if err != nil {
+			return nil, err
+		}
+		if 32+length > len(data) {
+			return nil, errors.New("ABI data too short")
+		}
+		value := append([]byte(nil), data[32:32+length]...)
+		if t.kind == "string" {
+			return string(value), nil
+		}
+		return value, nil
+	case "slice":
+		length, err := readABIOffset(data, 0)
+		if err != nil {
+			return nil, err
+		}
+		return decodeList(t.elem, length, data[32:])
+	case "array":
+		return decodeList(t.elem, t.length, data)
+	case "tuple":
+		values, err := decodeTuple(t.fields, data)
+		if err != nil {
+			return nil, err
+		}
+		tuple := make(map[string]any, len(values))
+		for i, field := range t.fields {
+			name := field.name
+			if name == "" {
+				name = strconv.Itoa(i)
+			}
+			tuple[name] = values[i]
+		}
+		return tuple, nil
+	}
+	return nil, fmt.Errorf("unsupported ABI type %q", t.kind)
+}
+
+func decodeList(elem *abiType, length int, data []byte) ([]any, error) {
+	fields := make([]abiField, length)
+	for i := range fields {
+		fields[i] = abiField{typ: elem}
+	}
+	return decodeTuple(fields, data)
+}
+
+func decodeTuple(fields []abiField, data []byte) ([]any, error) {
+	values := make([]any, len(fields))
+	head := 0
+	for i, field := range fields {
+		if field.typ.dynamic() {
+			offset, err := readABIOffset(data, head)
+			if err != nil {
+				return nil, err
+			}
+			if offset > len(data) {
+				return nil, errors.New("ABI offset out of range")
+			}
+			if values[i], err = field.typ.decode(data[offset:]); err != nil {
+				return nil, err
+			}
+			head += 32
+			continue
+		}
+		size := field.typ.headSize()
+		if head+size > len(data) {
+			return nil, errors.New("ABI data too short")
+		}
+		var err error
+		if values[i], err = field.typ.decode(data[head : head+size]); err != nil {
+			return nil, err
+		}
+		head += size
+	}
+	return values, nil
+}
+
+func readABIOffset(data []byte, at int) (int, error) {
+	if at+32 > len(data) {
+		return 0, errors.New("ABI data too short")
+	}
+	value := new(big.Int).SetBytes(data[at : at+32])
+	if !value.IsInt64() || value.Int64() > int64(len(data)) {
+		return 0, errors.New("ABI length or offset out of range")
+	}
+	return int(value.Int64()), nil
+}
+
+func abiSignature(name string, inputs []ABIArgument) (string, error) {
+	types := make([]string, len(inputs))
+	for i, input := range inputs {
+		typ, err := parseABIType(input.Type, input.Components)
+		if err != nil {
+			return "", fmt.Errorf("error parsing %s argument %d: %w", name, i, err)
+		}
+		types[i] = typ.canonical()
+	}
+	return name + "(" + strings.Join(types, ",") + ")", nil
+}
+
+func keccak256(data []byte) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(data)
+	return hash.Sum(nil)
+}
+
+func decodeHex(value string) ([]byte, error) {
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "0x"), "0X")
+	if len(value)%2 == 1 {
+		value = "0" + value
+	}
+	return hex.DecodeString(value)
+}
+
+func (a *ABI) DecodeInput(input string) (*DecodedCall, error) {
+	data, err := decodeHex(input)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding input data: %w", err)
+	}
+	if len(data) < 4 {
+		return nil, errors.New("input data has no method selector")
+	}
+	selector := "0x" + hex.EncodeToString(data[:4])
+	method, ok := a.Methods[selector]
+	if !ok {
+		return nil, fmt.Errorf("unknown method selector %s", selector)
+	}
+	args, err := decodeArguments(method.Inputs, data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("error decoding %s arguments: %w", method.Name, err)
+	}
+	return &DecodedCall{Selector: selector, Name: method.Name, Signature: method.Signature, Args: args}, nil
+}
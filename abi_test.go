@@ -0,0 +1,115 @@
+package oklink
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+const testEventABI = `[
+	{"type": "event", "name": "Transfer", "inputs": [
+		{"name": "from", "type": "address", "indexed": true},
+		{"name": "to", "type": "address", "indexed": true},
+		{"name": "value", "type": "uint256", "indexed": false}
+	]},
+	{"type": "event", "name": "Memo", "inputs": [
+		{"name": "sender", "type": "address", "indexed": true},
+		{"name": "note", "type": "string", "indexed": false},
+		{"name": "values", "type": "uint256[]", "indexed": false},
+		{"name": "delta", "type": "int8", "indexed": false}
+	]}
+]`
+
+func abiWord(value int64) string {
+	if value < 0 {
+		return strings.Repeat("f", 64-16) + fmt.Sprintf("%016x", uint64(value))
+	}
+	return fmt.Sprintf("%064x", value)
+}
+
+func TestParseABIEventTopics(t *testing.T) {
+	abi, err := ParseABI([]byte(testEventABI))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	transfer := "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	if event, ok := abi.Events[transfer]; !ok || event.Signature != "Transfer(address,address,uint256)" {
+		t.Errorf("Expected Transfer event under its topic, got %+v", abi.Events)
+	}
+}
+
+func TestDecodeTransferLog(t *testing.T) {
+	abi, _ := ParseABI([]byte(testEventABI))
+
+	log := Log{
+		Topics: []string{
+			"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+			"0x000000000000000000000000" + strings.Repeat("a", 40),
+			"0x000000000000000000000000" + strings.Repeat("b", 40),
+		},
+		Data: "0x" + abiWord(1000),
+	}
+	decoded, err := abi.DecodeLog(log)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if decoded.Name != "Transfer" {
+		t.Errorf("Expected Transfer, got %s", decoded.Name)
+	}
+	if from, _ := decoded.Arg("from"); from != "0x"+strings.Repeat("a", 40) {
+		t.Errorf("Expected from address, got %v", from)
+	}
+	if value, _ := decoded.Arg("value"); value.(*big.Int).Int64() != 1000 {
+		t.Errorf("Expected value 1000, got %v", value)
+	}
+}
+
+func TestDecodeDynamicLogData(t *testing.T) {
+	abi, _ := ParseABI([]byte(testEventABI))
+	var memo string
+	for topic, event := range abi.Events {
+		if event.Name == "Memo" {
+			memo = topic
+		}
+	}
+
+	data := abiWord(0x60) + abiWord(0xa0) + abiWord(-1) +
+		abiWord(2) + fmt.Sprintf("%-64s", "6869")[:64] +
+		abiWord(2) + abiWord(1) + abiWord(2)
+	data = strings.ReplaceAll(data, " ", "0")
+
+	decoded, err := abi.DecodeLog(Log{Topics: []string{memo, "0x" + abiWord(0xcafe)}, Data: "0x" + data})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if note, _ := decoded.Arg("note"); note != "hi" {
+		t.Errorf("Expected note hi, got %v", note)
+	}
+	values, _ := decoded.Arg("values")
+	if list := values.([]any); len(list) != 2 || list[1].(*big.Int).Int64() != 2 {
+		t.Errorf("Expected values [1 2], got %v", values)
+	}
+	if delta, _ := decoded.Arg("delta"); delta.(*big.Int).Int64() != -1 {
+		t.Errorf("Expected delta -1, got %v", delta)
+	}
+}
+
+func TestDecodeLogRejectsTruncatedData(t *testing.T) {
+	abi, _ := ParseABI([]byte(testEventABI))
+
+	log := Log{
+		Topics: []string{
+			"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+			"0x" + abiWord(1),
+			"0x" + abiWord(2),
+		},
+		Data: "0x1234",
+	}
+	if _, err := abi.DecodeLog(log); err == nil {
+		t.Fatal("Expected error for truncated data, got nil")
+	}
+}
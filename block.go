@@ -0,0 +1,209 @@
+package oklink
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+type BlockFills struct {
+	ChainFullName  string `json:"chainFullName"`
+	ChainShortName string `json:"chainShortName"`
+	Hash           string `json:"hash"`
+	Height         string `json:"height"`
+	Validator      string `json:"validator"`
+	BlockTime      string `json:"blockTime"`
+	TxnCount       string `json:"txnCount"`
+	Amount         string `json:"amount"`
+	BlockSize      string `json:"blockSize"`
+	MineReward     string `json:"mineReward"`
+	TotalFee       string `json:"totalFee"`
+	FeeSymbol      string `json:"feeSymbol"`
+	OmmerBlock     string `json:"ommerBlock"`
+	MerkleRootHash string `json:"merkleRootHash"`
+	GasUsed        string `json:"gasUsed"`
+	GasLimit       string `json:"gasLimit"`
+	GasAvgPrice    string `json:"gasAvgPrice"`
+	State          string `json:"state"`
+	Burnt          string `json:"burnt"`
+	Miner          string `json:"miner"`
+	Nonce          string `json:"nonce"`
+	Confirm        string `json:"confirm"`
+	BaseFeePerGas  string `json:"baseFeePerGas"`
+}
+
+type BlockListPage struct {
+	PageInfo
+	ChainFullName  string         `json:"chainFullName"`
+	ChainShortName string         `json:"chainShortName"`
+	BlockList      []BlockSummary `json:"blockList"`
+}
+
+type BlockSummary struct {
+	Hash          string `json:"hash"`
+	Height        string `json:"height"`
+	Validator     string `json:"validator"`
+	BlockTime     string `json:"blockTime"`
+	TxnCount      string `json:"txnCount"`
+	BlockSize     string `json:"blockSize"`
+	MineReward    string `json:"mineReward"`
+	TotalFee      string `json:"totalFee"`
+	FeeSymbol     string `json:"feeSymbol"`
+	AvgFee        string `json:"avgFee"`
+	OmmerBlock    string `json:"ommerBlock"`
+	GasUsed       string `json:"gasUsed"`
+	GasLimit      string `json:"gasLimit"`
+	GasAvgPrice   string `json:"gasAvgPrice"`
+	State         string `json:"state"`
+	Burnt         string `json:"burnt"`
+	BaseFeePerGas string `json:"baseFeePerGas"`
+}
+
+type BlockTransactionPage struct {
+	PageInfo
+	ChainFullName  string             `json:"chainFullName"`
+	ChainShortName string             `json:"chainShortName"`
+	BlockList      []BlockTransaction `json:"blockList"`
+}
+
+type BlockTransaction struct {
+	TxId                 string `json:"txid"`
+	MethodId             string `json:"methodId"`
+	BlockHash            string `json:"blockHash"`
+	Height               string `json:"height"`
+	TransactionTime      string `json:"transactionTime"`
+	From                 string `json:"from"`
+	To                   string `json:"to"`
+	IsFromContract       bool   `json:"isFromContract"`
+	IsToContract         bool   `json:"isToContract"`
+	Amount               string `json:"amount"`
+	TransactionSymbol    string `json:"transactionSymbol"`
+	TxFee                string `json:"txfee"`
+	State                string `json:"state"`
+	TokenId              string `json:"tokenId"`
+	TokenContractAddress string `json:"tokenContractAddress"`
+}
+
+type BlockHeight struct {
+	Height    string `json:"height"`
+	BlockTime string `json:"blockTime"`
+}
+
+type BlockWithTransactions struct {
+	Block        BlockFills
+	Transactions []BlockTransaction
+}
+
+const blockTransactionPageLimit = 100
+
+func BlockDetails(height string) (*ApiResponse[[]BlockFills], error) {
+	return BlockDetailsContext(context.Background(), height)
+}
+
+func BlockDetailsContext(ctx context.Context, height string) (*ApiResponse[[]BlockFills], error) {
+	params := url.Values{}
+	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
+	params.Add("height", height)
+
+	url := fmt.Sprintf("%sapi/v5/explorer/block/block-fills?%s", BASE_URL, params.Encode())
+	return fetchApiContext[[]BlockFills](ctx, url)
+}
+
+func BlockList(height *string, page *string, limit *string) (*ApiResponse[[]BlockListPage], error) {
+	params := url.Values{}
+	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
+
+	if height != nil {
+		params.Add("height", *height)
+	}
+
+	if page != nil {
+		params.Add("page", *page)
+	}
+
+	if limit != nil {
+		params.Add("limit", *limit)
+	}
+
+	url := fmt.Sprintf("%sapi/v5/explorer/block/block-list?%s", BASE_URL, params.Encode())
+	return fetchApi[[]BlockListPage](url)
+}
+
+func BlockTransactionList(height string, protocolType *ProtocolType, page *string, limit *string) (*ApiResponse[[]BlockTransactionPage], error) {
+	return BlockTransactionListContext(context.Background(), height, protocolType, page, limit)
+}
+
+func BlockTransactionListContext(ctx context.Context, height string, protocolType *ProtocolType, page *string, limit *string) (*ApiResponse[[]BlockTransactionPage], error) {
+	params := url.Values{}
+	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
+	params.Add("height", height)
+
+	if protocolType != nil {
+		params.Add("protocolType", string(*protocolType))
+	}
+
+	if page != nil {
+		params.Add("page", *page)
+	}
+
+	if limit != nil {
+		params.Add("limit", *limit)
+	}
+
+	url := fmt.Sprintf("%sapi/v5/explorer/block/transaction-list?%s", BASE_URL, params.Encode())
+	return fetchApiContext[[]BlockTransactionPage](ctx, url)
+}
+
+func BlockHeightByTime(timestamp string, closest *string) (*ApiResponse[[]BlockHeight], error) {
+	params := url.Values{}
+	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
+	params.Add("time", timestamp)
+
+	if closest != nil {
+		params.Add("closest", *closest)
+	}
+
+	url := fmt.Sprintf("%sapi/v5/explorer/block/block-height-by-time?%s", BASE_URL, params.Encode())
+	return fetchApi[[]BlockHeight](url)
+}
+
+func BlockTransactionListAll(height string, protocolType *ProtocolType) ([]BlockTransaction, error) {
+	return BlockTransactionListAllContext(context.Background(), height, protocolType)
+}
+
+func BlockTransactionListAllContext(ctx context.Context, height string, protocolType *ProtocolType) (txs []BlockTransaction, err error) {
+	ctx, span := startPageSpan(ctx, "block/transaction-list")
+	defer func() { endSpan(span, err) }()
+
+	limit := strconv.Itoa(blockTransactionPageLimit)
+	for page := 1; ; page++ {
+		pageNumber := strconv.Itoa(page)
+		response, err := BlockTransactionListContext(ctx, height, protocolType, &pageNumber, &limit)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching page %d of block %s transactions: %w", page, height, err)
+		}
+		if len(response.Data) == 0 {
+			return txs, nil
+		}
+		txs = append(txs, response.Data[0].BlockList...)
+		if !response.Data[0].hasNext() {
+			return txs, nil
+		}
+	}
+}
+
+func BlockWithAllTransactions(height string) (*BlockWithTransactions, error) {
+	response, err := BlockDetails(height)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Data) == 0 {
+		return nil, fmt.Errorf("block %s not found", height)
+	}
+	txs, err := BlockTransactionListAll(height, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &BlockWithTransactions{Block: response.Data[0], Transactions: txs}, nil
+}
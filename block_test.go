@@ -0,0 +1,77 @@
+package oklink
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBlockDetails(t *testing.T) {
+	mockResponse := `{"code": 0, "data": [{"hash": "0xblock", "height": "100", "txnCount": "3"}], "msg": "success"}`
+	server := setupMockServer(mockResponse, http.StatusOK)
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	response, err := BlockDetails("100")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Data[0].Hash != "0xblock" {
+		t.Errorf("Expected hash 0xblock, got %s", response.Data[0].Hash)
+	}
+}
+
+func TestBlockHeightByTime(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`{"code": 0, "data": [{"height": "123", "blockTime": "1700000000000"}], "msg": ""}`))
+	}))
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	closest := "before"
+	response, err := BlockHeightByTime("1700000000000", &closest)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Data[0].Height != "123" {
+		t.Errorf("Expected height 123, got %s", response.Data[0].Height)
+	}
+	if !strings.Contains(query, "closest=before") || !strings.Contains(query, "time=1700000000000") {
+		t.Errorf("Expected time and closest params, got %s", query)
+	}
+}
+
+func TestBlockWithAllTransactions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/block/block-fills"):
+			w.Write([]byte(`{"code": 0, "data": [{"hash": "0xblock", "height": "100"}], "msg": ""}`))
+		case strings.HasSuffix(r.URL.Path, "/block/transaction-list"):
+			page := r.URL.Query().Get("page")
+			fmt.Fprintf(w, `{"code": 0, "data": [{"page": "%s", "totalPage": "2", "blockList": [{"txid": "0xtx%s"}]}], "msg": ""}`, page, page)
+		}
+	}))
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	block, err := BlockWithAllTransactions("100")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if block.Block.Hash != "0xblock" {
+		t.Errorf("Expected hash 0xblock, got %s", block.Block.Hash)
+	}
+	if len(block.Transactions) != 2 || block.Transactions[1].TxId != "0xtx2" {
+		t.Errorf("Expected transactions from both pages, got %+v", block.Transactions)
+	}
+}
@@ -0,0 +1,381 @@
+package oklink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type BudgetPeriod string
+
+const (
+	Daily   BudgetPeriod = "daily"
+	Monthly BudgetPeriod = "monthly"
+)
+
+var ErrBudgetExceeded = errors.New("credit budget exceeded")
+
+// DefaultCreditCosts are the relative costs of the batch and statistics endpoints; everything else
+// costs Budget.DefaultCost. Adjust them to match your plan.
+var DefaultCreditCosts = map[string]float64{
+	"address/balance-multi":                   5,
+	"address/token-balance-multi":             5,
+	"address/normal-transaction-list-multi":   5,
+	"address/internal-transaction-list-multi": 5,
+	"address/token-transaction-list-multi":    5,
+	"token/token-transaction-list-multi":      5,
+	"transaction/transaction-multi":           5,
+	"transaction/internal-transaction-multi":  5,
+	"transaction/token-transfer-multi":        5,
+	"tokenprice/price-multi":                  5,
+	"blockchain/stats":                        3,
+	"token/position-statistics":               3,
+}
+
+// BudgetLimit caps credits spent in a period. Crossing Soft reports a warning once per period;
+// a request that would take usage past Hard is refused. Zero disables either threshold.
+type BudgetLimit struct {
+	Period BudgetPeriod
+	Soft   float64
+	Hard   float64
+}
+
+type BudgetWarning struct {
+	Period BudgetPeriod
+	Scope  string
+	Used   float64
+	Soft   float64
+}
+
+type BudgetUsage struct {
+	Period BudgetPeriod
+	Scope  string
+	Used   float64
+	Limit  BudgetLimit
+}
+
+// Budget meters credits per endpoint and per caller tag. Counters are kept in Store, so any
+// CacheBackend that survives restarts (DiskCache, RedisCache) persists them. Processes sharing a Store
+// only keep one exact count when it is a CounterBackend such as RedisCache; with other backends each
+// charge re-reads and rewrites the counter, so concurrent charges from another process can be lost.
+type Budget struct {
+	// Name prefixes the budget_remaining metric labels, keeping several budgets apart.
+	Name        string
+	Costs       map[string]float64
+	DefaultCost float64
+	// Limits apply to all requests, EndpointLimits and TagLimits to one endpoint or caller tag.
+	Limits         []BudgetLimit
+	EndpointLimits map[string][]BudgetLimit
+	TagLimits      map[string][]BudgetLimit
+	Store          CacheBackend
+	OnSoftLimit    func(warning BudgetWarning)
+	Location       *time.Location
+	Now            func() time.Time
+
+	mu      sync.Mutex
+	used    map[string]float64
+	warned  map[string]bool
+	buckets map[BudgetPeriod]string
+}
+
+type budgetTagKey struct{}
+
+// WithBudgetTag charges requests made with ctx to tag as well as to the endpoint.
+func WithBudgetTag(ctx context.Context, tag string) context.Context {
+	return context.WithValue(ctx, budgetTagKey{}, tag)
+}
+
+func budgetTag(ctx context.Context) string {
+	tag, _ := ctx.Value(budgetTagKey{}).(string)
+	return tag
+}
+
+func NewBudget(store CacheBackend) *Budget {
+	return &Budget{Costs: DefaultCreditCosts, DefaultCost: 1, Store: store, Location: time.UTC}
+}
+
+// Middleware refuses requests that would exceed a hard limit and charges every attempt that reached OKLink.
+func (b *Budget) Middleware() Middleware {
+	return func(next Handler) Handler {
+		return func(req *Request) *Response {
+			tag := budgetTag(req.HTTP.Context())
+			if err := b.Reserve(req.Endpoint, tag); err != nil {
+				return &Response{Err: err}
+			}
+			resp := next(req)
+			if resp.StatusCode == 0 {
+				b.Refund(req.Endpoint, tag)
+			}
+			return resp
+		}
+	}
+}
+
+func (b *Budget) Cost(endpoint string) float64 {
+	if cost, ok := b.Costs[endpoint]; ok {
+		return cost
+	}
+	return b.DefaultCost
+}
+
+// Reserve charges one call to endpoint against every scope it belongs to, or charges nothing when that
+// would take any scope past a hard limit. Refund gives the credits back for a call that never reached OKLink.
+func (b *Budget) Reserve(endpoint string, tag string) error {
+	cost := b.Cost(endpoint)
+	b.mu.Lock()
+	scopes := b.scopes(endpoint, tag)
+	for _, scope := range scopes {
+		for _, limit := range scope.limits {
+			// Counters only exist for these periods, so any other one would never be enforced.
+			if limit.Period != Daily && limit.Period != Monthly {
+				b.mu.Unlock()
+				return fmt.Errorf("invalid budget period %q for %s", limit.Period, scope.name)
+			}
+			if limit.Hard <= 0 {
+				continue
+			}
+			used, err := b.load(b.key(limit.Period, scope.name))
+			if err != nil {
+				b.mu.Unlock()
+				return err
+			}
+			if used+cost > limit.Hard {
+				b.mu.Unlock()
+				return fmt.Errorf("%s budget for %s at %g of %g credits: %w", limit.Period, scope.name, used, limit.Hard, ErrBudgetExceeded)
+			}
+		}
+	}
+	warnings, err := b.add(scopes, cost)
+	if err != nil {
+		// Another process sharing Store charged in between; give the credits back.
+		b.add(scopes, -cost)
+		for _, warning := range warnings {
+			delete(b.warned, b.key(warning.Period, warning.Scope))
+		}
+		warnings = nil
+	}
+	b.mu.Unlock()
+	b.warn(warnings)
+	return err
+}
+
+// Refund returns the credits Reserve charged for a call to endpoint.
+func (b *Budget) Refund(endpoint string, tag string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.add(b.scopes(endpoint, tag), -b.Cost(endpoint))
+}
+
+// Charge records one call to endpoint against every scope it belongs to without checking hard limits.
+func (b *Budget) Charge(endpoint string, tag string) {
+	b.mu.Lock()
+	warnings, _ := b.add(b.scopes(endpoint, tag), b.Cost(endpoint))
+	b.mu.Unlock()
+	b.warn(warnings)
+}
+
+// add adds delta to every counter of scopes. It reports ErrBudgetExceeded when a counter ends up past a
+// hard limit, which only happens when another process charged the same Store concurrently.
+func (b *Budget) add(scopes []budgetScope, delta float64) ([]BudgetWarning, error) {
+	var warnings []BudgetWarning
+	var exceeded error
+	for _, scope := range scopes {
+		for _, period := range []BudgetPeriod{Daily, Monthly} {
+			key := b.key(period, scope.name)
+			used, err := b.addCounter(key, delta, period)
+			if err != nil {
+				continue
+			}
+			for _, limit := range scope.limits {
+				if limit.Period != period {
+					continue
+				}
+				if delta > 0 && limit.Soft > 0 && used >= limit.Soft && !b.warned[key] {
+					b.warned[key] = true
+					warnings = append(warnings, BudgetWarning{Period: period, Scope: scope.name, Used: used, Soft: limit.Soft})
+				}
+				if limit.Hard > 0 {
+					if delta > 0 && used > limit.Hard && exceeded == nil {
+						exceeded = fmt.Errorf("%s budget for %s at %g of %g credits: %w", period, scope.name, used-delta, limit.Hard, ErrBudgetExceeded)
+					}
+					currentMetrics().SetRemainingBudget(b.metricName(period, scope.name), max(limit.Hard-used, 0))
+				}
+			}
+		}
+	}
+	return warnings, exceeded
+}
+
+func (b *Budget) addCounter(key string, delta float64, period BudgetPeriod) (float64, error) {
+	if counter, ok := b.Store.(CounterBackend); ok {
+		used, err := counter.Add(key, delta, budgetRetention(period))
+		if err != nil {
+			return 0, err
+		}
+		b.used[key] = used
+		return used, nil
+	}
+	used, err := b.load(key)
+	if err != nil {
+		return 0, err
+	}
+	used += delta
+	b.used[key] = used
+	if b.Store != nil {
+		// A failed write only loses this increment if the process restarts before the next one.
+		b.Store.Set(key, []byte(strconv.FormatFloat(used, 'f', -1, 64)), budgetRetention(period))
+	}
+	return used, nil
+}
+
+func (b *Budget) warn(warnings []BudgetWarning) {
+	for _, warning := range warnings {
+		if b.OnSoftLimit != nil {
+			b.OnSoftLimit(warning)
+			continue
+		}
+		slog.Warn("oklink credit budget soft limit reached", "period", warning.Period, "scope", warning.Scope, "used", warning.Used, "soft", warning.Soft)
+	}
+}
+
+// Usage reports the current period's usage for every limited scope and every scope charged so far.
+func (b *Budget) Usage() []BudgetUsage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	limits := map[string][]BudgetLimit{"total": b.Limits}
+	for endpoint, endpointLimits := range b.EndpointLimits {
+		limits["endpoint:"+endpoint] = endpointLimits
+	}
+	for tag, tagLimits := range b.TagLimits {
+		limits["tag:"+tag] = tagLimits
+	}
+
+	var usage []BudgetUsage
+	for _, period := range []BudgetPeriod{Daily, Monthly} {
+		prefix := b.key(period, "")
+		scopes := map[string]bool{}
+		for scope := range limits {
+			scopes[scope] = true
+		}
+		for key := range b.used {
+			if scope, ok := strings.CutPrefix(key, prefix); ok {
+				scopes[scope] = true
+			}
+		}
+		for scope := range scopes {
+			used, _ := b.load(prefix + scope)
+			entry := BudgetUsage{Period: period, Scope: scope, Used: used}
+			for _, limit := range limits[scope] {
+				if limit.Period == period {
+					entry.Limit = limit
+				}
+			}
+			usage = append(usage, entry)
+		}
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Scope != usage[j].Scope {
+			return usage[i].Scope < usage[j].Scope
+		}
+		return usage[i].Period < usage[j].Period
+	})
+	return usage
+}
+
+type budgetScope struct {
+	name   string
+	limits []BudgetLimit
+}
+
+func (b *Budget) scopes(endpoint string, tag string) []budgetScope {
+	scopes := []budgetScope{
+		{name: "total", limits: b.Limits},
+		{name: "endpoint:" + endpoint, limits: b.EndpointLimits[endpoint]},
+	}
+	if tag != "" {
+		scopes = append(scopes, budgetScope{name: "tag:" + tag, limits: b.TagLimits[tag]})
+	}
+	return scopes
+}
+
+// load returns the counter for key, re-reading Store so charges by other processes sharing it count.
+// A counter missing from Store, e.g. after an eviction, keeps the value last seen here.
+func (b *Budget) load(key string) (float64, error) {
+	if b.Store == nil {
+		return b.used[key], nil
+	}
+	value, ok, err := b.Store.Get(key)
+	if err != nil {
+		return 0, fmt.Errorf("error loading budget counter: %w", err)
+	}
+	if !ok {
+		return b.used[key], nil
+	}
+	used, err := strconv.ParseFloat(string(value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid budget counter %q: %w", value, err)
+	}
+	b.used[key] = used
+	return used, nil
+}
+
+// key names the counter for scope in the current period, dropping counters of a finished one.
+func (b *Budget) key(period BudgetPeriod, scope string) string {
+	if b.used == nil {
+		b.used, b.warned, b.buckets = map[string]float64{}, map[string]bool{}, map[BudgetPeriod]string{}
+	}
+	bucket := b.bucket(period)
+	prefix := "budget:" + string(period) + ":"
+	if previous, ok := b.buckets[period]; ok && previous != bucket {
+		stale := prefix + previous + ":"
+		for key := range b.used {
+			if strings.HasPrefix(key, stale) {
+				delete(b.used, key)
+				delete(b.warned, key)
+			}
+		}
+	}
+	b.buckets[period] = bucket
+	return prefix + bucket + ":" + scope
+}
+
+func (b *Budget) bucket(period BudgetPeriod) string {
+	now := b.now()
+	if period == Monthly {
+		return now.Format("2006-01")
+	}
+	return now.Format("2006-01-02")
+}
+
+func (b *Budget) metricName(period BudgetPeriod, scope string) string {
+	name := string(period) + ":" + scope
+	if b.Name != "" {
+		name = b.Name + ":" + name
+	}
+	return name
+}
+
+// budgetRetention keeps stored counters a little past the end of their period.
+func budgetRetention(period BudgetPeriod) time.Duration {
+	if period == Monthly {
+		return 32 * 24 * time.Hour
+	}
+	return 25 * time.Hour
+}
+
+func (b *Budget) now() time.Time {
+	now := time.Now()
+	if b.Now != nil {
+		now = b.Now()
+	}
+	if b.Location != nil {
+		now = now.In(b.Location)
+	}
+	return now
+}
@@ -0,0 +1,190 @@
+package oklink
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBudgetHardLimitStopsRequests(t *testing.T) {
+	calls := 0
+	server := setupCountingServer(&calls, func(string) string { return `{"code": 0, "data": {"balance": "1"}, "msg": ""}` })
+	defer server.Close()
+	BASE_URL = server.URL + "/"
+	defer ResetMiddleware()
+
+	budget := NewBudget(NewMemoryCache(0))
+	budget.Limits = []BudgetLimit{{Period: Daily, Hard: 2}}
+	Use(Retry(3, time.Millisecond), budget.Middleware())
+
+	for i := 0; i < 2; i++ {
+		if _, err := AddressInfo("0xbudget"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if _, err := AddressInfo("0xbudget"); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("Expected budget error, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected the refused call not to reach the API, got %d calls", calls)
+	}
+}
+
+func TestBudgetEndpointCostsAndTags(t *testing.T) {
+	server := setupMockServer(`{"code": 0, "data": [], "msg": ""}`, http.StatusOK)
+	defer server.Close()
+	BASE_URL = server.URL + "/"
+	defer ResetMiddleware()
+
+	var warnings []BudgetWarning
+	budget := NewBudget(nil)
+	budget.TagLimits = map[string][]BudgetLimit{"reports": {{Period: Monthly, Soft: 8, Hard: 12}}}
+	budget.OnSoftLimit = func(warning BudgetWarning) { warnings = append(warnings, warning) }
+	Use(budget.Middleware())
+
+	ctx := WithBudgetTag(context.Background(), "reports")
+	for i := 0; i < 2; i++ {
+		if _, err := TokenPricesContext(ctx, []Address{"0xtoken"}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if _, err := TokenPricesContext(ctx, []Address{"0xtoken"}); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("Expected the tag budget to stop the third batch call, got %v", err)
+	}
+	if _, err := TokenPrices([]Address{"0xtoken"}); err != nil {
+		t.Fatalf("Expected untagged calls to pass, got %v", err)
+	}
+	if len(warnings) != 1 || warnings[0].Scope != "tag:reports" || warnings[0].Used != 10 {
+		t.Errorf("Expected one soft warning at 10 credits, got %+v", warnings)
+	}
+
+	usage := map[string]float64{}
+	for _, entry := range budget.Usage() {
+		usage[string(entry.Period)+" "+entry.Scope] = entry.Used
+	}
+	if usage["daily endpoint:tokenprice/price-multi"] != 15 || usage["monthly tag:reports"] != 10 || usage["daily total"] != 15 {
+		t.Errorf("Unexpected usage %v", usage)
+	}
+}
+
+func TestBudgetPersistsAndRollsOver(t *testing.T) {
+	store, err := NewDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	now := time.Date(2026, 3, 31, 23, 0, 0, 0, time.UTC)
+	newBudget := func() *Budget {
+		budget := NewBudget(store)
+		budget.Limits = []BudgetLimit{{Period: Daily, Hard: 3}, {Period: Monthly, Hard: 4}}
+		budget.Now = func() time.Time { return now }
+		return budget
+	}
+
+	first := newBudget()
+	first.Charge("address/address-summary", "")
+	first.Charge("address/address-summary", "")
+
+	restarted := newBudget()
+	restarted.Charge("address/address-summary", "")
+	if err := restarted.Reserve("address/address-summary", ""); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("Expected counters to survive a restart, got %v", err)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if err := restarted.Reserve("address/address-summary", ""); err != nil {
+		t.Errorf("Expected a new day and month to reset the budget, got %v", err)
+	}
+}
+
+func TestBudgetReserveIsAtomic(t *testing.T) {
+	budget := NewBudget(NewMemoryCache(0))
+	budget.Limits = []BudgetLimit{{Period: Daily, Hard: 5}}
+
+	var reserved atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if budget.Reserve("address/address-summary", "") == nil {
+				reserved.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if reserved.Load() != 5 {
+		t.Errorf("Expected exactly 5 reservations, got %d", reserved.Load())
+	}
+}
+
+func TestBudgetRefundsUnsentRequests(t *testing.T) {
+	server := setupMockServer(`{"code": 0, "data": {}, "msg": ""}`, http.StatusOK)
+	BASE_URL = server.URL + "/"
+	server.Close()
+	defer ResetMiddleware()
+
+	budget := NewBudget(nil)
+	budget.Limits = []BudgetLimit{{Period: Daily, Hard: 1}}
+	Use(budget.Middleware())
+
+	for i := 0; i < 2; i++ {
+		if _, err := AddressInfo("0xdown"); err == nil || errors.Is(err, ErrBudgetExceeded) {
+			t.Fatalf("Expected a transport error, got %v", err)
+		}
+	}
+	for _, entry := range budget.Usage() {
+		if entry.Used != 0 {
+			t.Errorf("Expected failed round-trips to be refunded, got %+v", entry)
+		}
+	}
+}
+
+func TestBudgetSharesStoreAcrossProcesses(t *testing.T) {
+	for _, c := range []struct {
+		name  string
+		store func() CacheBackend
+	}{
+		{"memory", func() CacheBackend { return NewMemoryCache(0) }},
+		{"redis", func() CacheBackend {
+			addr, commands := setupRedisServer(t)
+			t.Cleanup(func() {
+				if joined := strings.Join(*commands, "\n"); !strings.Contains(joined, "INCRBYFLOAT ") || strings.Contains(joined, "SET ") {
+					t.Errorf("Expected redis counters to use INCRBYFLOAT, got %q", *commands)
+				}
+			})
+			return NewRedisCache(addr)
+		}},
+	} {
+		store := c.store()
+		first, second := NewBudget(store), NewBudget(store)
+		first.Limits = []BudgetLimit{{Period: Daily, Hard: 3}}
+		second.Limits = first.Limits
+
+		for _, budget := range []*Budget{first, second, first} {
+			if err := budget.Reserve("address/address-summary", ""); err != nil {
+				t.Fatalf("%s: expected no error, got %v", c.name, err)
+			}
+		}
+		if err := second.Reserve("address/address-summary", ""); !errors.Is(err, ErrBudgetExceeded) {
+			t.Errorf("%s: expected both budgets to count against one total, got %v", c.name, err)
+		}
+	}
+}
+
+func TestBudgetRejectsUnknownPeriod(t *testing.T) {
+	budget := NewBudget(nil)
+	budget.EndpointLimits = map[string][]BudgetLimit{"address/address-summary": {{Period: "weekly", Hard: 1}}}
+
+	err := budget.Reserve("address/address-summary", "")
+	if err == nil || !strings.Contains(err.Error(), `"weekly"`) {
+		t.Errorf("Expected the unknown period to be rejected, got %v", err)
+	}
+	if err := budget.Reserve("address/other", ""); err != nil {
+		t.Errorf("Expected other endpoints to be unaffected, got %v", err)
+	}
+}
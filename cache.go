@@ -0,0 +1,222 @@
+package oklink
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+type CacheBackend interface {
+	// Get reports a miss with ok == false; expired entries are misses.
+	Get(key string) (value []byte, ok bool, err error)
+	// Set stores value; a ttl of zero or less keeps it until evicted.
+	Set(key string, value []byte, ttl time.Duration) error
+	Delete(key string) error
+}
+
+// CounterBackend is implemented by backends that can add to a stored counter atomically, so budgets in
+// several processes sharing the backend keep one count.
+type CounterBackend interface {
+	// Add adds delta to the counter at key, creating it at zero, and returns the new value.
+	Add(key string, delta float64, ttl time.Duration) (float64, error)
+}
+
+type CachePolicy interface {
+	// CacheTTL decides whether a successful response may be cached and for how long; zero means forever.
+	CacheTTL(endpoint string, params url.Values, body []byte) (ttl time.Duration, ok bool)
+}
+
+type CacheStats struct {
+	Hits    int64
+	Misses  int64
+	Stores  int64
+	Skipped int64
+	Errors  int64
+}
+
+type Cache struct {
+	Backend CacheBackend
+	Policy  CachePolicy
+
+	hits    atomic.Int64
+	misses  atomic.Int64
+	stores  atomic.Int64
+	skipped atomic.Int64
+	errors  atomic.Int64
+}
+
+type FinalityPolicy struct {
+	// FinalityDepth is how many confirmations make a block or transaction immutable.
+	FinalityDepth int64
+	VolatileTTL   time.Duration
+	// DefaultTTL applies to everything else; zero disables caching for those endpoints.
+	DefaultTTL   time.Duration
+	LatestHeight func() (int64, error)
+}
+
+var responseCache atomic.Pointer[Cache]
+
+var volatileEndpoints = map[string]bool{
+	"transaction/unconfirmed-transaction-list": true,
+	"transaction/large-transaction-list":       true,
+	"blockchain/summary":                       true,
+	"blockchain/info":                          true,
+	"block/block-list":                         true,
+	"tokenprice/price-multi":                   true,
+}
+
+var heightEndpoints = map[string]string{
+	"block/address-balance-history": "height",
+	"token/supply-history":          "height",
+	"block/block-fills":             "height",
+	"block/transaction-list":        "height",
+	"log/by-block-and-address":      "endBlockHeight",
+}
+
+var transactionEndpoints = map[string]bool{
+	"transaction/transaction-fills": true,
+}
+
+// SetCache installs the cache used by every GET request; nil disables caching.
+func SetCache(cache *Cache) {
+	responseCache.Store(cache)
+}
+
+func NewCache(backend CacheBackend) *Cache {
+	return &Cache{Backend: backend, Policy: NewFinalityPolicy()}
+}
+
+func NewFinalityPolicy() *FinalityPolicy {
+	return &FinalityPolicy{
+		// Kaia blocks are final once produced, so one confirmation is enough by default.
+		FinalityDepth: 1,
+		VolatileTTL:   5 * time.Second,
+		DefaultTTL:    30 * time.Second,
+	}
+}
+
+func (c *Cache) Stats() CacheStats {
+	return CacheStats{
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+		Stores:  c.stores.Load(),
+		Skipped: c.skipped.Load(),
+		Errors:  c.errors.Load(),
+	}
+}
+
+func (c *Cache) lookup(rawURL string) ([]byte, bool) {
+	key, endpoint, _, err := cacheKey(rawURL)
+	if err != nil {
+		return nil, false
+	}
+	body, ok, err := c.Backend.Get(key)
+	if err != nil {
+		c.errors.Add(1)
+		currentMetrics().observeCache(endpoint, "error")
+		return nil, false
+	}
+	if !ok {
+		c.misses.Add(1)
+		currentMetrics().observeCache(endpoint, "miss")
+		return nil, false
+	}
+	c.hits.Add(1)
+	currentMetrics().observeCache(endpoint, "hit")
+	return body, true
+}
+
+// store is only called with bodies that decoded without an API error.
+func (c *Cache) store(rawURL string, body []byte) {
+	key, endpoint, params, err := cacheKey(rawURL)
+	if err != nil {
+		return
+	}
+	ttl, ok := c.Policy.CacheTTL(endpoint, params, body)
+	if !ok {
+		c.skipped.Add(1)
+		return
+	}
+	if err := c.Backend.Set(key, body, ttl); err != nil {
+		c.errors.Add(1)
+		return
+	}
+	c.stores.Add(1)
+}
+
+func (p *FinalityPolicy) CacheTTL(endpoint string, params url.Values, body []byte) (time.Duration, bool) {
+	if volatileEndpoints[endpoint] {
+		return p.VolatileTTL, p.VolatileTTL > 0
+	}
+	if param, ok := heightEndpoints[endpoint]; ok {
+		if p.finalHeight(params.Get(param)) {
+			return 0, true
+		}
+		return p.VolatileTTL, p.VolatileTTL > 0
+	}
+	if transactionEndpoints[endpoint] {
+		if p.finalTransactions(body) {
+			return 0, true
+		}
+		return p.VolatileTTL, p.VolatileTTL > 0
+	}
+	return p.DefaultTTL, p.DefaultTTL > 0
+}
+
+func (p *FinalityPolicy) finalHeight(value string) bool {
+	height, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || height <= 0 {
+		return false
+	}
+	latest, err := p.latestHeight()
+	if err != nil {
+		return false
+	}
+	return confirmations(latest, height) >= p.FinalityDepth
+}
+
+func (p *FinalityPolicy) finalTransactions(body []byte) bool {
+	var response ApiResponse[[]struct {
+		Height  string `json:"height"`
+		State   string `json:"state"`
+		Confirm string `json:"confirm"`
+	}]
+	if err := json.Unmarshal(body, &response); err != nil || len(response.Data) == 0 {
+		return false
+	}
+	for _, tx := range response.Data {
+		confirm, _ := strconv.ParseInt(tx.Confirm, 10, 64)
+		if tx.Height == "" || isPendingState(tx.State) || confirm < p.FinalityDepth {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *FinalityPolicy) latestHeight() (int64, error) {
+	if p.LatestHeight != nil {
+		return p.LatestHeight()
+	}
+	return latestBlockHeight()
+}
+
+func cacheKey(rawURL string) (string, string, url.Values, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", nil, err
+	}
+	params := parsed.Query()
+	// Encode sorts parameters, so equivalent URLs share one entry.
+	key := parsed.Scheme + "://" + parsed.Host + parsed.Path + "?" + params.Encode()
+	return key, apiEndpoint(parsed.Path), params, nil
+}
+
+func apiEndpoint(path string) string {
+	if i := strings.Index(path, "api/v5/explorer/"); i >= 0 {
+		return path[i+len("api/v5/explorer/"):]
+	}
+	return strings.TrimPrefix(path, "/")
+}
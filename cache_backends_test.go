@@ -0,0 +1,68 @@
+package oklink
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewMemoryCache(2)
+	cache.Set("a", []byte("1"), 0)
+	cache.Set("b", []byte("2"), 0)
+	cache.Get("a")
+	cache.Set("c", []byte("3"), 0)
+
+	if _, ok, _ := cache.Get("b"); ok {
+		t.Error("Expected b to be evicted")
+	}
+	if value, ok, _ := cache.Get("a"); !ok || string(value) != "1" {
+		t.Errorf("Expected a to survive, got %q", value)
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Expected 2 entries, got %d", cache.Len())
+	}
+}
+
+func TestMemoryCacheExpiry(t *testing.T) {
+	now := time.Now()
+	cache := NewMemoryCache(0)
+	cache.Now = func() time.Time { return now }
+	cache.Set("a", []byte("1"), time.Second)
+
+	if _, ok, _ := cache.Get("a"); !ok {
+		t.Fatal("Expected a fresh entry")
+	}
+	now = now.Add(time.Second)
+	if _, ok, _ := cache.Get("a"); ok {
+		t.Error("Expected the entry to expire")
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	cache, err := NewDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	now := time.Now()
+	cache.Now = func() time.Time { return now }
+
+	if err := cache.Set("forever", []byte(`{"code": 0}`), 0); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := cache.Set("short", []byte("x"), time.Minute); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if value, ok, err := cache.Get("forever"); err != nil || !ok || string(value) != `{"code": 0}` {
+		t.Errorf("Expected stored entry, got %q, %v, %v", value, ok, err)
+	}
+	if _, ok, _ := cache.Get("short"); ok {
+		t.Error("Expected the short entry to expire")
+	}
+
+	cache.Delete("forever")
+	if _, ok, _ := cache.Get("forever"); ok {
+		t.Error("Expected the entry to be deleted")
+	}
+}
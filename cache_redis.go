@@ -0,0 +1,198 @@
+package oklink
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type RedisCache struct {
+	Addr     string
+	Password string
+	DB       int
+	Prefix   string
+	Timeout  time.Duration
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+type redisError string
+
+func (e redisError) Error() string { return "redis error: " + string(e) }
+
+func NewRedisCache(addr string) *RedisCache {
+	return &RedisCache{Addr: addr, Prefix: "oklink:", Timeout: 2 * time.Second}
+}
+
+func (c *RedisCache) Get(key string) ([]byte, bool, error) {
+	reply, err := c.do("GET", c.Prefix+key)
+	if err != nil {
+		return nil, false, err
+	}
+	if reply == nil {
+		return nil, false, nil
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("unexpected GET reply %T", reply)
+	}
+	return value, true, nil
+}
+
+func (c *RedisCache) Set(key string, value []byte, ttl time.Duration) error {
+	args := []string{"SET", c.Prefix + key, string(value)}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	}
+	_, err := c.do(args...)
+	return err
+}
+
+func (c *RedisCache) Delete(key string) error {
+	_, err := c.do("DEL", c.Prefix+key)
+	return err
+}
+
+func (c *RedisCache) Add(key string, delta float64, ttl time.Duration) (float64, error) {
+	reply, err := c.do("INCRBYFLOAT", c.Prefix+key, strconv.FormatFloat(delta, 'f', -1, 64))
+	if err != nil {
+		return 0, err
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return 0, fmt.Errorf("unexpected INCRBYFLOAT reply %T", reply)
+	}
+	total, err := strconv.ParseFloat(string(value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid INCRBYFLOAT reply %q: %w", value, err)
+	}
+	if ttl > 0 {
+		if _, err := c.do("PEXPIRE", c.Prefix+key, strconv.FormatInt(ttl.Milliseconds(), 10)); err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+func (c *RedisCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn, c.reader = nil, nil
+	return err
+}
+
+func (c *RedisCache) do(args ...string) (any, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		if err := c.connect(); err != nil {
+			return nil, err
+		}
+	}
+	reply, err := c.roundTrip(args)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		// The connection state is unknown after an I/O error, so the next call reconnects.
+		c.conn.Close()
+		c.conn, c.reader = nil, nil
+	}
+	return reply, err
+}
+
+func (c *RedisCache) connect() error {
+	conn, err := net.DialTimeout("tcp", c.Addr, c.Timeout)
+	if err != nil {
+		return fmt.Errorf("error connecting to redis: %w", err)
+	}
+	c.conn, c.reader = conn, bufio.NewReader(conn)
+	if c.Password != "" {
+		if _, err := c.roundTrip([]string{"AUTH", c.Password}); err != nil {
+			c.conn.Close()
+			c.conn, c.reader = nil, nil
+			return err
+		}
+	}
+	if c.DB != 0 {
+		if _, err := c.roundTrip([]string{"SELECT", strconv.Itoa(c.DB)}); err != nil {
+			c.conn.Close()
+			c.conn, c.reader = nil, nil
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *RedisCache) roundTrip(args []string) (any, error) {
+	if c.Timeout > 0 {
+		c.conn.SetDeadline(time.Now().Add(c.Timeout))
+	}
+	var command strings.Builder
+	fmt.Fprintf(&command, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&command, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(c.conn, command.String()); err != nil {
+		return nil, fmt.Errorf("error writing redis command: %w", err)
+	}
+	return readRESP(c.reader)
+}
+
+func readRESP(reader *bufio.Reader) (any, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("error reading redis reply: %w", err)
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("empty redis reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis bulk length %q", line)
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, fmt.Errorf("error reading redis reply: %w", err)
+		}
+		return data[:size], nil
+	case '*':
+		count, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis array length %q", line)
+		}
+		if count < 0 {
+			return nil, nil
+		}
+		items := make([]any, count)
+		for i := range items {
+			if items[i], err = readRESP(reader); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("unknown redis reply %q", line)
+}
@@ -0,0 +1,109 @@
+package oklink
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func setupRedisServer(t *testing.T) (string, *[]string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	var mu sync.Mutex
+	var commands []string
+	store := map[string]string{}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					reply, err := readRESP(reader)
+					if err != nil {
+						return
+					}
+					items := reply.([]any)
+					args := make([]string, len(items))
+					for i, item := range items {
+						args[i] = string(item.([]byte))
+					}
+					mu.Lock()
+					commands = append(commands, strings.Join(args, " "))
+					switch args[0] {
+					case "GET":
+						if value, ok := store[args[1]]; ok {
+							fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(value), value)
+						} else {
+							conn.Write([]byte("$-1\r\n"))
+						}
+					case "SET":
+						store[args[1]] = args[2]
+						conn.Write([]byte("+OK\r\n"))
+					case "INCRBYFLOAT":
+						current, _ := strconv.ParseFloat(store[args[1]], 64)
+						delta, _ := strconv.ParseFloat(args[2], 64)
+						value := strconv.FormatFloat(current+delta, 'f', -1, 64)
+						store[args[1]] = value
+						fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(value), value)
+					case "PEXPIRE":
+						conn.Write([]byte(":1\r\n"))
+					case "DEL":
+						delete(store, args[1])
+						conn.Write([]byte(":1\r\n"))
+					default:
+						conn.Write([]byte("-ERR unknown command\r\n"))
+					}
+					mu.Unlock()
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String(), &commands
+}
+
+func TestRedisCache(t *testing.T) {
+	addr, commands := setupRedisServer(t)
+	cache := NewRedisCache(addr)
+	defer cache.Close()
+
+	if _, ok, err := cache.Get("missing"); err != nil || ok {
+		t.Fatalf("Expected a miss, got %v, %v", ok, err)
+	}
+	if err := cache.Set("key", []byte("line1\r\nline2"), 1500*time.Millisecond); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	value, ok, err := cache.Get("key")
+	if err != nil || !ok || string(value) != "line1\r\nline2" {
+		t.Errorf("Expected stored value, got %q, %v, %v", value, ok, err)
+	}
+	if err := cache.Delete("key"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if (*commands)[1] != "SET oklink:key line1\r\nline2 PX 1500" {
+		t.Errorf("Unexpected SET command %q", (*commands)[1])
+	}
+}
+
+func TestRedisCacheReplyError(t *testing.T) {
+	addr, _ := setupRedisServer(t)
+	cache := NewRedisCache(addr)
+	cache.Password = "secret"
+	defer cache.Close()
+
+	if _, _, err := cache.Get("key"); err == nil || !strings.Contains(err.Error(), "unknown command") {
+		t.Errorf("Expected AUTH to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+package oklink
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func setupCountingServer(requests *int, respond func(path string) string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		w.Write([]byte(respond(r.URL.Path)))
+	}))
+}
+
+func TestCacheStoresConfirmedTransactionsForever(t *testing.T) {
+	requests := 0
+	server := setupCountingServer(&requests, func(string) string {
+		return `{"code": 0, "data": [{"txid": "0xtx", "height": "100", "state": "success", "confirm": "20"}], "msg": ""}`
+	})
+	defer server.Close()
+	BASE_URL = server.URL + "/"
+
+	backend := NewMemoryCache(16)
+	cache := NewCache(backend)
+	SetCache(cache)
+	defer SetCache(nil)
+
+	for i := 0; i < 3; i++ {
+		if _, err := TransactionDetails("0xtx"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Expected one request, got %d", requests)
+	}
+	stats := cache.Stats()
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Stores != 1 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+	for _, element := range backend.entries {
+		if !element.Value.(*memoryEntry).expires.IsZero() {
+			t.Errorf("Expected confirmed transaction to be cached without expiry")
+		}
+	}
+}
+
+func TestCacheNeverStoresErrors(t *testing.T) {
+	requests := 0
+	server := setupCountingServer(&requests, func(string) string {
+		return `{"code": 50011, "data": [], "msg": "Too Many Requests"}`
+	})
+	defer server.Close()
+	BASE_URL = server.URL + "/"
+
+	cache := NewCache(NewMemoryCache(16))
+	SetCache(cache)
+	defer SetCache(nil)
+
+	for i := 0; i < 2; i++ {
+		if _, err := TransactionDetails("0xtx"); err == nil {
+			t.Fatal("Expected API error")
+		}
+	}
+	if requests != 2 || cache.Stats().Stores != 0 {
+		t.Errorf("Expected errors to bypass the cache, got %d requests and %+v", requests, cache.Stats())
+	}
+}
+
+func TestFinalityPolicy(t *testing.T) {
+	policy := NewFinalityPolicy()
+	policy.FinalityDepth = 10
+	policy.LatestHeight = func() (int64, error) { return 100, nil }
+
+	var requested string
+	body := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.RequestURI()
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+	BASE_URL = server.URL + "/"
+
+	cases := []struct {
+		name  string
+		fetch func() error
+		body  string
+		ttl   time.Duration
+		ok    bool
+	}{
+		{"old balance history", func() error { _, err := AddressBalanceHistory("0xaddress", "80", nil); return err }, "", 0, true},
+		{"recent balance history", func() error { _, err := AddressBalanceHistory("0xaddress", "95", nil); return err }, "", policy.VolatileTTL, true},
+		{"balance details", func() error { _, err := AddressBalanceDetails("0xaddress", "token_20", nil, nil, nil); return err }, "", policy.DefaultTTL, true},
+		{"old supply history", func() error { _, err := TokenSupplyHistory("0xtoken", "80"); return err }, "", 0, true},
+		{"unconfirmed list", func() error { _, err := UnconfirmedTransactionList(nil, nil); return err }, "", policy.VolatileTTL, true},
+		{"pending transaction", func() error { _, err := TransactionDetails("0xtx"); return err }, `{"code": 0, "data": [{"height": "", "state": "pending"}], "msg": ""}`, policy.VolatileTTL, true},
+		{"missing transaction", func() error { _, err := TransactionDetails("0xtx"); return err }, "", policy.VolatileTTL, true},
+		{"final transaction", func() error { _, err := TransactionDetails("0xtx"); return err }, `{"code": 0, "data": [{"height": "90", "state": "fail", "confirm": "11"}], "msg": ""}`, 0, true},
+		{"address summary", func() error { _, err := AddressInfo("0xaddress"); return err }, `{"code": 0, "data": {}, "msg": ""}`, policy.DefaultTTL, true},
+	}
+	for _, c := range cases {
+		body = c.body
+		if body == "" {
+			body = `{"code": 0, "data": [], "msg": ""}`
+		}
+		if err := c.fetch(); err != nil {
+			t.Fatalf("%s: expected no error, got %v", c.name, err)
+		}
+		_, endpoint, params, err := cacheKey(server.URL + requested)
+		if err != nil {
+			t.Fatalf("%s: expected a cache key, got %v", c.name, err)
+		}
+		ttl, ok := policy.CacheTTL(endpoint, params, []byte(body))
+		if ttl != c.ttl || ok != c.ok {
+			t.Errorf("%s (%s): expected %v/%v, got %v/%v", c.name, endpoint, c.ttl, c.ok, ttl, ok)
+		}
+	}
+
+	policy.DefaultTTL = 0
+	if _, ok := policy.CacheTTL("address/address-summary", nil, nil); ok {
+		t.Error("Expected a zero default TTL to disable caching")
+	}
+}
+
+func TestCacheKeyIgnoresParameterOrder(t *testing.T) {
+	a, endpoint, _, _ := cacheKey("https://www.oklink.com/api/v5/explorer/block/block-fills?height=1&CHAIN_SHORTNAME=KLAYTN")
+	b, _, _, _ := cacheKey("https://www.oklink.com/api/v5/explorer/block/block-fills?CHAIN_SHORTNAME=KLAYTN&height=1")
+	if a != b || endpoint != "block/block-fills" || !strings.HasPrefix(a, "https://www.oklink.com/") {
+		t.Errorf("Unexpected keys %q, %q for %q", a, b, endpoint)
+	}
+}
+
+func TestSetCacheWhileRequestsAreInFlight(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code": 0, "data": [{"txid": "0xtx", "height": "100", "state": "success", "confirm": "20"}], "msg": ""}`))
+	}))
+	defer server.Close()
+	BASE_URL = server.URL + "/"
+	defer SetCache(nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			SetCache(NewCache(NewMemoryCache(16)))
+		}()
+		go func(i int) {
+			defer wg.Done()
+			if _, err := TransactionDetails(fmt.Sprintf("0xtx%d", i)); err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
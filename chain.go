@@ -0,0 +1,122 @@
+package oklink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+type BlockchainSummary struct {
+	ChainFullName               string `json:"chainFullName"`
+	ChainShortName              string `json:"chainShortName"`
+	Symbol                      string `json:"symbol"`
+	LastHeight                  string `json:"lastHeight"`
+	LastBlockTime               string `json:"lastBlockTime"`
+	CirculatingSupply           string `json:"circulatingSupply"`
+	CirculatingSupplyProportion string `json:"circulatingSupplyProportion"`
+	Transactions                string `json:"transactions"`
+}
+
+type BlockchainInfo struct {
+	ChainFullName          string `json:"chainFullName"`
+	ChainShortName         string `json:"chainShortName"`
+	Symbol                 string `json:"symbol"`
+	Rank                   string `json:"rank"`
+	MineReward             string `json:"mineReward"`
+	Transactions           string `json:"transactions"`
+	TransactionsIn24h      string `json:"transactionsIn24h"`
+	Tps                    string `json:"tps"`
+	LastHeight             string `json:"lastHeight"`
+	LastBlockTime          string `json:"lastBlockTime"`
+	AvgBlockInterval       string `json:"avgBlockInterval"`
+	AvgBlockSize           string `json:"avgBlockSize"`
+	AvgGasPrice            string `json:"avgGasPrice"`
+	AvgTransactionFee      string `json:"avgTransactionFee"`
+	TotalAddresses         string `json:"totalAddresses"`
+	ActiveAddresses24h     string `json:"activeAddresses24h"`
+	UnconfirmedTransaction string `json:"unconfirmedTransaction"`
+}
+
+type BlockchainStatsPage struct {
+	PageInfo
+	ChainFullName  string            `json:"chainFullName"`
+	ChainShortName string            `json:"chainShortName"`
+	StatsHistory   []BlockchainStats `json:"statsHistory"`
+}
+
+type BlockchainStats struct {
+	Time                  string `json:"time"`
+	NewAddressCount       string `json:"newAddressCount"`
+	TotalAddressCount     string `json:"totalAddressCount"`
+	ContractAddressCount  string `json:"contractAddressCount"`
+	TransactionCount      string `json:"transactionCount"`
+	TotalTransactionCount string `json:"totalTransactionCount"`
+	BlockCount            string `json:"blockCount"`
+	AvgBlockInterval      string `json:"avgBlockInterval"`
+	AvgBlockSize          string `json:"avgBlockSize"`
+	AvgGasPrice           string `json:"avgGasPrice"`
+	AvgTransactionFee     string `json:"avgTransactionFee"`
+	TotalFee              string `json:"totalFee"`
+	Tps                   string `json:"tps"`
+}
+
+func ChainSummary() (*ApiResponse[[]BlockchainSummary], error) {
+	return ChainSummaryContext(context.Background())
+}
+
+func ChainSummaryContext(ctx context.Context) (*ApiResponse[[]BlockchainSummary], error) {
+	params := url.Values{}
+	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
+
+	url := fmt.Sprintf("%sapi/v5/explorer/blockchain/summary?%s", BASE_URL, params.Encode())
+	return fetchApiContext[[]BlockchainSummary](ctx, url)
+}
+
+func ChainInfo() (*ApiResponse[[]BlockchainInfo], error) {
+	params := url.Values{}
+	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
+
+	url := fmt.Sprintf("%sapi/v5/explorer/blockchain/info?%s", BASE_URL, params.Encode())
+	return fetchApi[[]BlockchainInfo](url)
+}
+
+func ChainDailyStats(startTime *string, endTime *string, page *string, limit *string) (*ApiResponse[[]BlockchainStatsPage], error) {
+	params := url.Values{}
+	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
+
+	if startTime != nil {
+		params.Add("startTime", *startTime)
+	}
+
+	if endTime != nil {
+		params.Add("endTime", *endTime)
+	}
+
+	if page != nil {
+		params.Add("page", *page)
+	}
+
+	if limit != nil {
+		params.Add("limit", *limit)
+	}
+
+	url := fmt.Sprintf("%sapi/v5/explorer/blockchain/stats?%s", BASE_URL, params.Encode())
+	return fetchApi[[]BlockchainStatsPage](url)
+}
+
+func latestBlockHeight() (int64, error) {
+	return latestBlockHeightContext(context.Background())
+}
+
+func latestBlockHeightContext(ctx context.Context) (int64, error) {
+	response, err := ChainSummaryContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(response.Data) == 0 {
+		return 0, errors.New("blockchain summary returned no data")
+	}
+	return strconv.ParseInt(response.Data[0].LastHeight, 10, 64)
+}
@@ -0,0 +1,47 @@
+package oklink
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestChainSummary(t *testing.T) {
+	mockResponse := `{"code": 0, "data": [{"chainShortName": "KLAYTN", "lastHeight": "150000000", "transactions": "987654"}], "msg": ""}`
+	server := setupMockServer(mockResponse, http.StatusOK)
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	height, err := latestBlockHeight()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if height != 150000000 {
+		t.Errorf("Expected height 150000000, got %d", height)
+	}
+}
+
+func TestChainDailyStats(t *testing.T) {
+	var path, query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, query = r.URL.Path, r.URL.RawQuery
+		w.Write([]byte(`{"code": 0, "data": [{"page": "1", "limit": "2", "totalPage": "1", "statsHistory": [{"time": "1700000000000", "transactionCount": "42", "avgGasPrice": "0.000000025", "tps": "0.5"}]}], "msg": ""}`))
+	}))
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	start, end := "1699900000000", "1700000000000"
+	response, err := ChainDailyStats(&start, &end, nil, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.HasSuffix(path, "/blockchain/stats") || !strings.Contains(query, "startTime="+start) || !strings.Contains(query, "endTime="+end) {
+		t.Errorf("Unexpected request %s?%s", path, query)
+	}
+	if stats := response.Data[0].StatsHistory; len(stats) != 1 || stats[0].TransactionCount != "42" {
+		t.Errorf("Expected one day with 42 transactions, got %+v", stats)
+	}
+}
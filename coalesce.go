@@ -0,0 +1,104 @@
+package oklink
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+type flightGroup struct {
+	disabled  atomic.Bool
+	coalesced atomic.Int64
+
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done    chan struct{}
+	waiters int
+	body    []byte
+	err     error
+	// cancelled is set when the leader's own context ended the fetch, so waiters must not inherit it.
+	cancelled bool
+}
+
+var inflight = &flightGroup{calls: map[string]*flightCall{}}
+
+// SetRequestCoalescing toggles sharing one round-trip between concurrent identical GET requests.
+func SetRequestCoalescing(enabled bool) {
+	inflight.disabled.Store(!enabled)
+}
+
+// CoalescedRequests reports how many requests were answered by another caller's round-trip.
+func CoalescedRequests() int64 {
+	return inflight.coalesced.Load()
+}
+
+// do runs fetch once per canonical URL at a time. The leader gets fetch's value; callers that waited on
+// another get shared == true and the raw body to decode themselves. The body is only kept when it has
+// waiters or retain is set, otherwise its buffer goes back to the pool. Waiters stop waiting when their
+// own ctx ends, and retry when the leader's ctx ended its fetch, one of them taking over as leader.
+func (g *flightGroup) do(ctx context.Context, rawURL string, retain bool, fetch func(buf *bytes.Buffer) (any, error)) (value any, body []byte, shared bool, err error) {
+	key, _, _, keyErr := cacheKey(rawURL)
+	if g.disabled.Load() || keyErr != nil {
+		return g.run(retain, fetch)
+	}
+
+	for {
+		g.mu.Lock()
+		call, ok := g.calls[key]
+		if !ok {
+			break
+		}
+		call.waiters++
+		g.mu.Unlock()
+		g.coalesced.Add(1)
+		select {
+		case <-call.done:
+			if !call.cancelled {
+				return nil, call.body, true, call.err
+			}
+			g.coalesced.Add(-1)
+		case <-ctx.Done():
+			g.mu.Lock()
+			call.waiters--
+			g.mu.Unlock()
+			g.coalesced.Add(-1)
+			return nil, nil, false, ctx.Err()
+		}
+	}
+	call := &flightCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	buf := getBuffer()
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		waiters := call.waiters
+		g.mu.Unlock()
+		if waiters > 0 || retain {
+			call.body = buf.Bytes()
+			body = call.body
+		} else {
+			putBuffer(buf)
+		}
+		call.err = err
+		call.cancelled = err != nil && ctx.Err() != nil
+		close(call.done)
+	}()
+	value, err = fetch(buf)
+	return value, nil, false, err
+}
+
+func (g *flightGroup) run(retain bool, fetch func(buf *bytes.Buffer) (any, error)) (any, []byte, bool, error) {
+	if !retain {
+		value, err := fetch(nil)
+		return value, nil, false, err
+	}
+	buf := new(bytes.Buffer)
+	value, err := fetch(buf)
+	return value, buf.Bytes(), false, err
+}
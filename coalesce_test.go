@@ -0,0 +1,157 @@
+package oklink
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestConcurrentIdenticalRequestsShareOneRoundTrip(t *testing.T) {
+	var requests atomic.Int64
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		w.Write([]byte(`{"code": 0, "data": {"address": "0xabc", "balance": "1.5"}, "msg": ""}`))
+	}))
+	defer server.Close()
+	BASE_URL = server.URL + "/"
+
+	before := CoalescedRequests()
+	var wg sync.WaitGroup
+	results := make([]string, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			response, err := AddressInfo("0xabc")
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+				return
+			}
+			results[i] = response.Data.Balance
+		}(i)
+	}
+
+	waitForCoalesced(before, 4)
+	close(release)
+	wg.Wait()
+
+	if requests.Load() != 1 {
+		t.Errorf("Expected one round-trip, got %d", requests.Load())
+	}
+	for _, balance := range results {
+		if balance != "1.5" {
+			t.Errorf("Expected every caller to get the shared result, got %v", results)
+			break
+		}
+	}
+}
+
+func TestRequestCoalescingDisabled(t *testing.T) {
+	SetRequestCoalescing(false)
+	defer SetRequestCoalescing(true)
+
+	before := CoalescedRequests()
+	value, body, shared, err := inflight.do(context.Background(), "https://www.oklink.com/?a=1", true, func(buf *bytes.Buffer) (any, error) {
+		buf.WriteString("x")
+		return 1, nil
+	})
+	if err != nil || shared || value != 1 || string(body) != "x" {
+		t.Errorf("Expected a direct call, got %v, %q, %v, %v", value, body, shared, err)
+	}
+	if CoalescedRequests() != before {
+		t.Error("Expected no coalesced requests while disabled")
+	}
+}
+
+func waitForCoalesced(before, n int64) {
+	deadline := time.Now().Add(2 * time.Second)
+	for CoalescedRequests()-before < n && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCoalescedWaiterHonoursItsContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{"code": 0, "data": {"address": "0xabc", "balance": "1.5"}, "msg": ""}`))
+	}))
+	defer server.Close()
+	defer close(release)
+	BASE_URL = server.URL + "/"
+
+	before := CoalescedRequests()
+	go AddressInfo("0xabc")
+	for {
+		inflight.mu.Lock()
+		leading := len(inflight.calls) > 0
+		inflight.mu.Unlock()
+		if leading {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := AddressInfoContext(ctx, "0xabc")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the waiter's deadline, got %v", err)
+	}
+	if CoalescedRequests() != before {
+		t.Errorf("Expected an abandoned wait not to count as coalesced, got %d", CoalescedRequests()-before)
+	}
+}
+
+func TestCoalescedWaiterRetriesWhenLeaderIsCancelled(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			<-r.Context().Done()
+			return
+		}
+		w.Write([]byte(`{"code": 0, "data": {"address": "0xabc", "balance": "1.5"}, "msg": ""}`))
+	}))
+	defer server.Close()
+	BASE_URL = server.URL + "/"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leader := make(chan error, 1)
+	go func() {
+		_, err := AddressInfoContext(ctx, "0xabc")
+		leader <- err
+	}()
+	for requests.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	before := CoalescedRequests()
+	waiter := make(chan error, 1)
+	go func() {
+		response, err := AddressInfo("0xabc")
+		if err == nil && response.Data.Balance != "1.5" {
+			err = errors.New("unexpected balance " + response.Data.Balance)
+		}
+		waiter <- err
+	}()
+	waitForCoalesced(before, 1)
+	cancel()
+
+	if err := <-leader; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the leader to be cancelled, got %v", err)
+	}
+	if err := <-waiter; err != nil {
+		t.Errorf("Expected the waiter to retry and succeed, got %v", err)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("Expected the waiter to lead a second round-trip, got %d", requests.Load())
+	}
+}
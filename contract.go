@@ -0,0 +1,208 @@
+package oklink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type CodeFormat string
+
+const (
+	CodeFormatSingleFile   CodeFormat = "solidity-single-file"
+	CodeFormatStandardJSON CodeFormat = "solidity-standard-json-input"
+	CodeFormatVyper        CodeFormat = "vyper"
+)
+
+type VerificationStatus string
+
+const (
+	VerificationPending VerificationStatus = "Pending"
+	VerificationSuccess VerificationStatus = "Success"
+	VerificationFail    VerificationStatus = "Fail"
+)
+
+var ErrContractNotVerified = errors.New("contract is not verified")
+
+type ContractInfo struct {
+	SourceCode           string        `json:"sourceCode"`
+	ContractName         string        `json:"contractName"`
+	CompilerVersion      string        `json:"compilerVersion"`
+	Optimization         string        `json:"optimization"`
+	OptimizationRuns     string        `json:"optimizationRuns"`
+	ContractAbi          string        `json:"contractAbi"`
+	EvmVersion           string        `json:"evmVersion"`
+	ViaIr                string        `json:"viaIr"`
+	LibraryInfo          []LibraryInfo `json:"libraryInfo"`
+	ConstructorArguments string        `json:"constructorArguments"`
+	LicenseType          string        `json:"licenseType"`
+	Proxy                string        `json:"proxy"`
+	Implementation       string        `json:"implementation"`
+	SwarmSource          string        `json:"swarmSource"`
+}
+
+type LibraryInfo struct {
+	LibraryName    string `json:"libraryName"`
+	LibraryAddress string `json:"libraryAddress"`
+}
+
+type SourceCodeVerification struct {
+	ChainShortName       string        `json:"chainShortName"`
+	ContractAddress      string        `json:"contractAddress"`
+	ContractName         string        `json:"contractName"`
+	SourceCode           string        `json:"sourceCode"`
+	CodeFormat           CodeFormat    `json:"codeFormat"`
+	CompilerVersion      string        `json:"compilerVersion"`
+	Optimization         string        `json:"optimization"`
+	OptimizationRuns     string        `json:"optimizationRuns,omitempty"`
+	ContractAbi          string        `json:"contractAbi,omitempty"`
+	EvmVersion           string        `json:"evmVersion,omitempty"`
+	ViaIr                bool          `json:"viaIr,omitempty"`
+	ConstructorArguments string        `json:"constructorArguments,omitempty"`
+	LibraryInfo          []LibraryInfo `json:"libraryInfo,omitempty"`
+	LicenseType          string        `json:"licenseType,omitempty"`
+}
+
+type ProxyVerification struct {
+	ChainShortName         string `json:"chainShortName"`
+	ProxyContractAddress   string `json:"proxyContractAddress"`
+	ExpectedImplementation string `json:"expectedImplementation,omitempty"`
+}
+
+type verificationResultRequest struct {
+	ChainShortName string `json:"chainShortName"`
+	Guid           string `json:"guid"`
+}
+
+func VerifiedContractInfo(contractAddress Address) (*ApiResponse[[]ContractInfo], error) {
+	params := url.Values{}
+	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
+	params.Add("contractAddress", string(contractAddress))
+
+	url := fmt.Sprintf("%sapi/v5/explorer/contract/verify-contract-info?%s", BASE_URL, params.Encode())
+	return fetchApi[[]ContractInfo](url)
+}
+
+func VerifiedContractABI(contractAddress Address) (*ABI, error) {
+	response, err := VerifiedContractInfo(contractAddress)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Data) == 0 || response.Data[0].ContractAbi == "" {
+		return nil, fmt.Errorf("contract %s: %w", contractAddress, ErrContractNotVerified)
+	}
+	return ParseABI([]byte(response.Data[0].ContractAbi))
+}
+
+func VerifySourceCode(verification SourceCodeVerification) (*ApiResponse[[]string], error) {
+	if verification.ContractAddress == "" || verification.SourceCode == "" || verification.CompilerVersion == "" {
+		return nil, errors.New("contractAddress, sourceCode and compilerVersion are required")
+	}
+	if verification.ChainShortName == "" {
+		verification.ChainShortName = CHAIN_SHORTNAME
+	}
+	if verification.CodeFormat == "" {
+		verification.CodeFormat = CodeFormatSingleFile
+	}
+
+	url := fmt.Sprintf("%sapi/v5/explorer/contract/verify-source-code", BASE_URL)
+	return postApi[[]string](url, verification)
+}
+
+func CheckVerifyResult(guid string) (*ApiResponse[[]string], error) {
+	return CheckVerifyResultContext(context.Background(), guid)
+}
+
+func CheckVerifyResultContext(ctx context.Context, guid string) (*ApiResponse[[]string], error) {
+	url := fmt.Sprintf("%sapi/v5/explorer/contract/check-verify-result", BASE_URL)
+	return postApiContext[[]string](ctx, url, verificationResultRequest{ChainShortName: CHAIN_SHORTNAME, Guid: guid})
+}
+
+func VerifyProxyContract(proxyContractAddress Address, expectedImplementation *Address) (*ApiResponse[[]string], error) {
+	verification := ProxyVerification{
+		ChainShortName:       CHAIN_SHORTNAME,
+		ProxyContractAddress: string(proxyContractAddress),
+	}
+
+	if expectedImplementation != nil {
+		verification.ExpectedImplementation = string(*expectedImplementation)
+	}
+
+	url := fmt.Sprintf("%sapi/v5/explorer/contract/verify-proxy-contract", BASE_URL)
+	return postApi[[]string](url, verification)
+}
+
+func CheckProxyVerifyResult(guid string) (*ApiResponse[[]string], error) {
+	return CheckProxyVerifyResultContext(context.Background(), guid)
+}
+
+func CheckProxyVerifyResultContext(ctx context.Context, guid string) (*ApiResponse[[]string], error) {
+	url := fmt.Sprintf("%sapi/v5/explorer/contract/check-proxy-verify-result", BASE_URL)
+	return postApiContext[[]string](ctx, url, verificationResultRequest{ChainShortName: CHAIN_SHORTNAME, Guid: guid})
+}
+
+func WaitForVerification(ctx context.Context, guid string, interval time.Duration) (VerificationStatus, error) {
+	return waitForResult(ctx, guid, interval, CheckVerifyResultContext, parseVerificationStatus)
+}
+
+func WaitForProxyVerification(ctx context.Context, guid string, interval time.Duration) (VerificationStatus, error) {
+	return waitForResult(ctx, guid, interval, CheckProxyVerifyResultContext, parseProxyVerificationStatus)
+}
+
+// waitForResult polls check until the result is a recognised success or failure. Pending and unrecognised
+// results keep it polling until ctx ends.
+func waitForResult(ctx context.Context, guid string, interval time.Duration, check func(context.Context, string) (*ApiResponse[[]string], error), parse func(string) VerificationStatus) (VerificationStatus, error) {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		response, err := check(ctx, guid)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return VerificationPending, ctxErr
+		}
+		if err != nil {
+			return VerificationPending, err
+		}
+		if len(response.Data) > 0 {
+			result := response.Data[0]
+			switch parse(result) {
+			case VerificationSuccess:
+				return VerificationSuccess, nil
+			case VerificationFail:
+				return VerificationFail, fmt.Errorf("verification %s failed: %s", guid, result)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return VerificationPending, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func parseVerificationStatus(result string) VerificationStatus {
+	lower := strings.ToLower(result)
+	switch {
+	case lower == "success", strings.Contains(lower, "pass"), strings.Contains(lower, "already verified"):
+		return VerificationSuccess
+	case strings.Contains(lower, "fail"), strings.Contains(lower, "unable"), strings.Contains(lower, "error"):
+		return VerificationFail
+	}
+	return VerificationPending
+}
+
+func parseProxyVerificationStatus(result string) VerificationStatus {
+	lower := strings.ToLower(result)
+	switch {
+	case strings.Contains(lower, "success"):
+		return VerificationSuccess
+	case strings.Contains(lower, "fail"), strings.Contains(lower, "not detected"), strings.Contains(lower, "error"):
+		return VerificationFail
+	}
+	return VerificationPending
+}
@@ -0,0 +1,124 @@
+package oklink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifiedContractABI(t *testing.T) {
+	mockResponse := `{"code": 0, "data": [{"contractName": "Token", "contractAbi": "[{\"type\":\"function\",\"name\":\"transfer\",\"inputs\":[{\"name\":\"to\",\"type\":\"address\"},{\"name\":\"value\",\"type\":\"uint256\"}]}]"}], "msg": ""}`
+	server := setupMockServer(mockResponse, http.StatusOK)
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	abi, err := VerifiedContractABI("0xtoken")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if method, ok := abi.Methods["0xa9059cbb"]; !ok || method.Name != "transfer" {
+		t.Errorf("Expected transfer selector 0xa9059cbb, got %+v", abi.Methods)
+	}
+}
+
+func TestVerifySourceCodeAndWait(t *testing.T) {
+	var submitted SourceCodeVerification
+	checks := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/verify-source-code"):
+			json.NewDecoder(r.Body).Decode(&submitted)
+			w.Write([]byte(`{"code": 0, "data": ["guid-1"], "msg": ""}`))
+		case strings.HasSuffix(r.URL.Path, "/check-verify-result"):
+			checks++
+			if checks < 3 {
+				w.Write([]byte(`{"code": 0, "data": ["Pending"], "msg": ""}`))
+				return
+			}
+			w.Write([]byte(`{"code": 0, "data": ["Success"], "msg": ""}`))
+		}
+	}))
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	response, err := VerifySourceCode(SourceCodeVerification{
+		ContractAddress: "0xtoken",
+		ContractName:    "Token",
+		SourceCode:      "contract Token {}",
+		CompilerVersion: "v0.8.24+commit.e11b9ed9",
+		Optimization:    "1",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if submitted.CodeFormat != CodeFormatSingleFile || submitted.ChainShortName != CHAIN_SHORTNAME {
+		t.Errorf("Expected defaults to be filled in, got %+v", submitted)
+	}
+
+	status, err := WaitForVerification(context.Background(), response.Data[0], time.Millisecond)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if status != VerificationSuccess || checks != 3 {
+		t.Errorf("Expected success after 3 checks, got %s after %d", status, checks)
+	}
+}
+
+func TestWaitForProxyVerificationFailure(t *testing.T) {
+	mockResponse := `{"code": 0, "data": ["A corresponding implementation contract was unfortunately not detected for the proxy address."], "msg": ""}`
+	server := setupMockServer(mockResponse, http.StatusOK)
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	status, err := WaitForProxyVerification(context.Background(), "guid-2", time.Millisecond)
+	if err == nil || status != VerificationFail {
+		t.Errorf("Expected failed verification, got %s with %v", status, err)
+	}
+}
+
+func TestWaitForVerificationPollsThroughUnknownStatus(t *testing.T) {
+	checks := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		checks++
+		if checks == 1 {
+			w.Write([]byte(`{"code": 0, "data": ["In progress"], "msg": ""}`))
+			return
+		}
+		w.Write([]byte(`{"code": 0, "data": ["Pass - Verified"], "msg": ""}`))
+	}))
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	status, err := WaitForVerification(context.Background(), "guid-3", time.Millisecond)
+	if err != nil || status != VerificationSuccess || checks != 2 {
+		t.Errorf("Expected success after an unknown status, got %s with %v after %d checks", status, err, checks)
+	}
+}
+
+func TestWaitForVerificationReturnsContextError(t *testing.T) {
+	server := setupMockServer(`{"code": 0, "data": ["Pending in queue"], "msg": ""}`, http.StatusOK)
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	status, err := WaitForVerification(ctx, "guid-4", time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) || status != VerificationPending {
+		t.Errorf("Expected the context error while pending, got %s with %v", status, err)
+	}
+}
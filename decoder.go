@@ -0,0 +1,271 @@
+package oklink
+
+import (
+	"context"
+	_ "embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+type DecodeSource string
+
+const (
+	DecodeSourceRegistry  DecodeSource = "registry"
+	DecodeSourceVerified  DecodeSource = "verified"
+	DecodeSourceSignature DecodeSource = "signature"
+)
+
+//go:embed signatures.txt
+var embeddedSignatures string
+
+type InputDecoder struct {
+	// FetchVerified enables looking up verified ABIs through the contract endpoints.
+	FetchVerified bool
+	VerifiedABI   func(Address) (*ABI, error)
+
+	mu         sync.Mutex
+	registry   map[string]*ABI
+	verified   map[string]*ABI
+	signatures map[string][]ABIMethod
+}
+
+func NewInputDecoder() *InputDecoder {
+	d := &InputDecoder{
+		FetchVerified: true,
+		VerifiedABI:   VerifiedContractABI,
+		registry:      map[string]*ABI{},
+		verified:      map[string]*ABI{},
+		signatures:    map[string][]ABIMethod{},
+	}
+	if err := d.loadSignatures(embeddedSignatures); err != nil {
+		panic(fmt.Sprintf("invalid embedded signature database: %v", err))
+	}
+	return d
+}
+
+// loadSignatures adds every "selector signature" line of text, checking the selector against the signature.
+func (d *InputDecoder) loadSignatures(text string) error {
+	var errs []error
+	for i, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) != 2 {
+			errs = append(errs, fmt.Errorf("line %d: expected a selector and a signature, got %q", i+1, line))
+			continue
+		}
+		method, err := parseSignature(fields[1])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", i+1, err))
+			continue
+		}
+		if method.Selector != fields[0] {
+			errs = append(errs, fmt.Errorf("line %d: selector of %s is %s, not %s", i+1, method.Signature, method.Selector, fields[0]))
+			continue
+		}
+		d.AddSignature(method.Signature)
+	}
+	return errors.Join(errs...)
+}
+
+func (d *InputDecoder) Register(contractAddress Address, abi *ABI) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.registry[strings.ToLower(string(contractAddress))] = abi
+}
+
+func (d *InputDecoder) AddSignature(signature string) error {
+	method, err := parseSignature(signature)
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, known := range d.signatures[method.Selector] {
+		if known.Signature == method.Signature {
+			return nil
+		}
+	}
+	d.signatures[method.Selector] = append(d.signatures[method.Selector], method)
+	return nil
+}
+
+func (d *InputDecoder) Decode(contractAddress Address, input string) (*DecodedCall, error) {
+	data, err := decodeHex(input)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding input data: %w", err)
+	}
+	if len(data) < 4 {
+		return nil, errors.New("input data has no method selector")
+	}
+	selector := "0x" + hex.EncodeToString(data[:4])
+
+	if abi := d.registered(contractAddress); abi != nil {
+		if _, ok := abi.Methods[selector]; ok {
+			return decodeCall(abi, input, DecodeSourceRegistry)
+		}
+	}
+
+	// A failed lookup falls through to the signature database; the error is reported on the result.
+	abi, fetchErr := d.verifiedABI(contractAddress)
+	if abi != nil {
+		if _, ok := abi.Methods[selector]; ok {
+			return decodeCall(abi, input, DecodeSourceVerified)
+		}
+	}
+
+	d.mu.Lock()
+	candidates := d.signatures[selector]
+	d.mu.Unlock()
+	// Selectors can collide, so the first candidate whose arguments decode cleanly wins.
+	for _, method := range candidates {
+		args, err := decodeArguments(method.Inputs, data[4:])
+		if err != nil {
+			continue
+		}
+		return &DecodedCall{Selector: selector, Name: method.Name, Signature: method.Signature, Source: DecodeSourceSignature, Args: args, FetchErr: fetchErr}, nil
+	}
+	return nil, errors.Join(fmt.Errorf("unknown method selector %s", selector), fetchErr)
+}
+
+func (d *InputDecoder) DecodeTransaction(tx TransactionFills) (*DecodedCall, error) {
+	if len(tx.OutputDetails) == 0 {
+		return nil, fmt.Errorf("transaction %s has no recipient", tx.TxId)
+	}
+	input := strings.TrimPrefix(tx.InputData, "0x")
+	if input == "" {
+		// Plain value transfers carry no calldata.
+		return nil, nil
+	}
+	return d.Decode(Address(tx.OutputDetails[0].OutputHash), tx.InputData)
+}
+
+func (d *InputDecoder) DecodeTransactionDetails(txId string) (*DecodedCall, error) {
+	tx, err := transactionFills(context.Background(), txId)
+	if err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		return nil, fmt.Errorf("transaction %s not found", txId)
+	}
+	return d.DecodeTransaction(*tx)
+}
+
+func (d *InputDecoder) registered(contractAddress Address) *ABI {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.registry[strings.ToLower(string(contractAddress))]
+}
+
+func (d *InputDecoder) verifiedABI(contractAddress Address) (*ABI, error) {
+	if !d.FetchVerified || d.VerifiedABI == nil || contractAddress == "" {
+		return nil, nil
+	}
+	key := strings.ToLower(string(contractAddress))
+	d.mu.Lock()
+	abi, ok := d.verified[key]
+	d.mu.Unlock()
+	if ok {
+		return abi, nil
+	}
+
+	abi, err := d.VerifiedABI(contractAddress)
+	if err != nil && !errors.Is(err, ErrContractNotVerified) {
+		return nil, fmt.Errorf("error fetching ABI for %s: %w", contractAddress, err)
+	}
+	// Unverified contracts are cached as nil so they are not looked up on every call.
+	d.mu.Lock()
+	d.verified[key] = abi
+	d.mu.Unlock()
+	return abi, nil
+}
+
+func decodeCall(abi *ABI, input string, source DecodeSource) (*DecodedCall, error) {
+	call, err := abi.DecodeInput(input)
+	if err != nil {
+		return nil, err
+	}
+	call.Source = source
+	return call, nil
+}
+
+func parseSignature(signature string) (ABIMethod, error) {
+	signature = strings.ReplaceAll(signature, " ", "")
+	open := strings.Index(signature, "(")
+	if open <= 0 || !strings.HasSuffix(signature, ")") {
+		return ABIMethod{}, fmt.Errorf("invalid function signature %q", signature)
+	}
+	name := signature[:open]
+	inputs, err := parseSignatureTypes(signature[open+1 : len(signature)-1])
+	if err != nil {
+		return ABIMethod{}, fmt.Errorf("invalid function signature %q: %w", signature, err)
+	}
+	canonical, err := abiSignature(name, inputs)
+	if err != nil {
+		return ABIMethod{}, err
+	}
+	return ABIMethod{
+		Name:      name,
+		Inputs:    inputs,
+		Signature: canonical,
+		Selector:  "0x" + hex.EncodeToString(keccak256([]byte(canonical))[:4]),
+	}, nil
+}
+
+func parseSignatureTypes(list string) ([]ABIArgument, error) {
+	if list == "" {
+		return nil, nil
+	}
+	var args []ABIArgument
+	depth, start := 0, 0
+	for i := 0; i <= len(list); i++ {
+		if i < len(list) {
+			switch list[i] {
+			case '(':
+				depth++
+				continue
+			case ')':
+				depth--
+				if depth < 0 {
+					return nil, errors.New("unbalanced parentheses")
+				}
+				continue
+			case ',':
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		if depth != 0 {
+			return nil, errors.New("unbalanced parentheses")
+		}
+		arg, err := parseSignatureType(list[start:i])
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		start = i + 1
+	}
+	return args, nil
+}
+
+func parseSignatureType(typ string) (ABIArgument, error) {
+	if typ == "" {
+		return ABIArgument{}, errors.New("empty argument type")
+	}
+	if !strings.HasPrefix(typ, "(") {
+		return ABIArgument{Type: typ}, nil
+	}
+	closing := strings.LastIndex(typ, ")")
+	components, err := parseSignatureTypes(typ[1:closing])
+	if err != nil {
+		return ABIArgument{}, err
+	}
+	return ABIArgument{Type: "tuple" + typ[closing+1:], Components: components}, nil
+}
@@ -0,0 +1,151 @@
+package oklink
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const transferInput = "0xa9059cbb" +
+	"000000000000000000000000ab5801a7d398351b8be11c439e05c5b3259aec9b" +
+	"00000000000000000000000000000000000000000000000000000000000003e8"
+
+func TestEmbeddedSignatures(t *testing.T) {
+	decoder := &InputDecoder{signatures: map[string][]ABIMethod{}}
+	if err := decoder.loadSignatures(embeddedSignatures); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if lines := strings.Count(strings.TrimSpace(embeddedSignatures), "\n") + 1; len(decoder.signatures) != lines {
+		t.Errorf("Expected %d selectors, got %d", lines, len(decoder.signatures))
+	}
+}
+
+func TestLoadSignaturesReportsBadLines(t *testing.T) {
+	decoder := &InputDecoder{signatures: map[string][]ABIMethod{}}
+	err := decoder.loadSignatures("# comment\n0xa9059cbb transfer(address,uint256)\n0xdeadbeef transfer(address,uint256)\n0x12345678 broken(\n")
+	if err == nil || !strings.Contains(err.Error(), "line 3") || !strings.Contains(err.Error(), "line 4") {
+		t.Fatalf("Expected errors for lines 3 and 4, got %v", err)
+	}
+	if len(decoder.signatures) != 1 {
+		t.Errorf("Expected the valid line to be loaded, got %d selectors", len(decoder.signatures))
+	}
+}
+
+func TestInputDecoderSignatureDatabase(t *testing.T) {
+	decoder := NewInputDecoder()
+	decoder.FetchVerified = false
+
+	call, err := decoder.Decode("0xtoken", transferInput)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if call.Name != "transfer" || call.Source != DecodeSourceSignature || len(call.Args) != 2 {
+		t.Fatalf("Unexpected call %+v", call)
+	}
+	if call.Args[0].Value != "0xab5801a7d398351b8be11c439e05c5b3259aec9b" {
+		t.Errorf("Expected recipient address, got %v", call.Args[0].Value)
+	}
+	if amount, ok := call.Args[1].Value.(*big.Int); !ok || amount.Int64() != 1000 {
+		t.Errorf("Expected amount 1000, got %v", call.Args[1].Value)
+	}
+}
+
+func TestInputDecoderTupleSignature(t *testing.T) {
+	decoder := NewInputDecoder()
+	decoder.FetchVerified = false
+	if err := decoder.AddSignature("aggregate((address,bytes)[])"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	input := "0x252dba42" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"000000000000000000000000ab5801a7d398351b8be11c439e05c5b3259aec9b" +
+		"0000000000000000000000000000000000000000000000000000000000000040" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"1234000000000000000000000000000000000000000000000000000000000000"
+	call, err := decoder.Decode("0xmulticall", input)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	calls, ok := call.Args[0].Value.([]any)
+	if !ok || len(calls) != 1 {
+		t.Fatalf("Expected one call, got %#v", call.Args[0].Value)
+	}
+	entry := calls[0].(map[string]any)
+	if data, _ := entry["1"].([]byte); hex.EncodeToString(data) != "1234" {
+		t.Errorf("Expected call data 1234, got %#v", entry)
+	}
+}
+
+func TestInputDecoderPrefersRegistryAndVerified(t *testing.T) {
+	abi, err := ParseABI([]byte(`[{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}]}]`))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	lookups := 0
+	decoder := NewInputDecoder()
+	decoder.VerifiedABI = func(address Address) (*ABI, error) {
+		lookups++
+		if address == "0xverified" {
+			return abi, nil
+		}
+		return nil, ErrContractNotVerified
+	}
+	decoder.Register("0xREGISTERED", abi)
+
+	call, err := decoder.Decode("0xregistered", transferInput)
+	if err != nil || call.Source != DecodeSourceRegistry || call.Args[0].Name != "to" {
+		t.Fatalf("Expected registry decode, got %+v, %v", call, err)
+	}
+
+	call, err = decoder.Decode("0xverified", transferInput)
+	if err != nil || call.Source != DecodeSourceVerified {
+		t.Fatalf("Expected verified decode, got %+v, %v", call, err)
+	}
+
+	for i := 0; i < 2; i++ {
+		call, err = decoder.Decode("0xunverified", transferInput)
+		if err != nil || call.Source != DecodeSourceSignature {
+			t.Fatalf("Expected signature decode, got %+v, %v", call, err)
+		}
+	}
+	if lookups != 2 {
+		t.Errorf("Expected verified lookups to be cached, got %d lookups", lookups)
+	}
+
+	decoder.VerifiedABI = func(Address) (*ABI, error) { return nil, errors.New("timeout") }
+	call, err = decoder.Decode("0xother", transferInput)
+	if err != nil || call.Source != DecodeSourceSignature {
+		t.Fatalf("Expected signature decode after a failed lookup, got %+v, %v", call, err)
+	}
+	if call.FetchErr == nil || !strings.Contains(call.FetchErr.Error(), "timeout") {
+		t.Errorf("Expected lookup error on the result, got %v", call.FetchErr)
+	}
+	if _, err := decoder.Decode("0xother", "0x12345678"); err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Errorf("Expected unknown selector error to include the lookup error, got %v", err)
+	}
+}
+
+func TestDecodeTransactionDetails(t *testing.T) {
+	mockResponse := `{"code": 0, "data": [{"txid": "0xabc", "inputData": "` + transferInput + `", "outputDetails": [{"outputHash": "0xtoken", "isContract": true}]}], "msg": ""}`
+	server := setupMockServer(mockResponse, http.StatusOK)
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	decoder := NewInputDecoder()
+	decoder.FetchVerified = false
+	call, err := decoder.DecodeTransactionDetails("0xabc")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if call.Signature != "transfer(address,uint256)" {
+		t.Errorf("Expected transfer signature, got %s", call.Signature)
+	}
+}
@@ -0,0 +1,164 @@
+package etherscan
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+type Transaction struct {
+	BlockNumber       string `json:"blockNumber"`
+	TimeStamp         string `json:"timeStamp"`
+	Hash              string `json:"hash"`
+	Nonce             string `json:"nonce"`
+	BlockHash         string `json:"blockHash"`
+	TransactionIndex  string `json:"transactionIndex"`
+	From              string `json:"from"`
+	To                string `json:"to"`
+	Value             string `json:"value"`
+	Gas               string `json:"gas"`
+	GasPrice          string `json:"gasPrice"`
+	IsError           string `json:"isError"`
+	TxReceiptStatus   string `json:"txreceipt_status"`
+	Input             string `json:"input"`
+	ContractAddress   string `json:"contractAddress"`
+	CumulativeGasUsed string `json:"cumulativeGasUsed"`
+	GasUsed           string `json:"gasUsed"`
+	Confirmations     string `json:"confirmations"`
+	MethodId          string `json:"methodId"`
+	FunctionName      string `json:"functionName"`
+}
+
+type InternalTransaction struct {
+	BlockNumber     string `json:"blockNumber"`
+	TimeStamp       string `json:"timeStamp"`
+	Hash            string `json:"hash"`
+	From            string `json:"from"`
+	To              string `json:"to"`
+	Value           string `json:"value"`
+	ContractAddress string `json:"contractAddress"`
+	Input           string `json:"input"`
+	Type            string `json:"type"`
+	Gas             string `json:"gas"`
+	GasUsed         string `json:"gasUsed"`
+	TraceId         string `json:"traceId"`
+	IsError         string `json:"isError"`
+	ErrCode         string `json:"errCode"`
+}
+
+type TokenTransfer struct {
+	BlockNumber       string `json:"blockNumber"`
+	TimeStamp         string `json:"timeStamp"`
+	Hash              string `json:"hash"`
+	Nonce             string `json:"nonce"`
+	BlockHash         string `json:"blockHash"`
+	From              string `json:"from"`
+	ContractAddress   string `json:"contractAddress"`
+	To                string `json:"to"`
+	Value             string `json:"value"`
+	TokenID           string `json:"tokenID"`
+	TokenName         string `json:"tokenName"`
+	TokenSymbol       string `json:"tokenSymbol"`
+	TokenDecimal      string `json:"tokenDecimal"`
+	TransactionIndex  string `json:"transactionIndex"`
+	Gas               string `json:"gas"`
+	GasPrice          string `json:"gasPrice"`
+	GasUsed           string `json:"gasUsed"`
+	CumulativeGasUsed string `json:"cumulativeGasUsed"`
+	Input             string `json:"input"`
+	Confirmations     string `json:"confirmations"`
+}
+
+type AccountBalance struct {
+	Account string `json:"account"`
+	Balance string `json:"balance"`
+}
+
+func Balance(address string, tag *Tag) (*Response[string], error) {
+	if address == "" {
+		return nil, errEmptyAddress
+	}
+	params := url.Values{}
+	params.Add("module", "account")
+	params.Add("action", "balance")
+	params.Add("address", address)
+	params.Add("tag", string(tagOrLatest(tag)))
+
+	return fetchResult[string](params)
+}
+
+func BalanceMulti(addresses []string, tag *Tag) (*Response[[]AccountBalance], error) {
+	if len(addresses) == 0 || len(addresses) > 20 {
+		return nil, errors.New("between 1 and 20 addresses are required")
+	}
+	params := url.Values{}
+	params.Add("module", "account")
+	params.Add("action", "balancemulti")
+	params.Add("address", strings.Join(addresses, ","))
+	params.Add("tag", string(tagOrLatest(tag)))
+
+	return fetchResult[[]AccountBalance](params)
+}
+
+func TxList(address string, startBlock *string, endBlock *string, page *int, offset *int, sort *Sort) (*Response[[]Transaction], error) {
+	return accountList[Transaction]("txlist", address, nil, startBlock, endBlock, page, offset, sort)
+}
+
+func TxListInternal(address string, startBlock *string, endBlock *string, page *int, offset *int, sort *Sort) (*Response[[]InternalTransaction], error) {
+	return accountList[InternalTransaction]("txlistinternal", address, nil, startBlock, endBlock, page, offset, sort)
+}
+
+func TokenTx(address string, contractAddress *string, startBlock *string, endBlock *string, page *int, offset *int, sort *Sort) (*Response[[]TokenTransfer], error) {
+	return accountList[TokenTransfer]("tokentx", address, contractAddress, startBlock, endBlock, page, offset, sort)
+}
+
+func TokenNftTx(address string, contractAddress *string, startBlock *string, endBlock *string, page *int, offset *int, sort *Sort) (*Response[[]TokenTransfer], error) {
+	return accountList[TokenTransfer]("tokennfttx", address, contractAddress, startBlock, endBlock, page, offset, sort)
+}
+
+func Token1155Tx(address string, contractAddress *string, startBlock *string, endBlock *string, page *int, offset *int, sort *Sort) (*Response[[]TokenTransfer], error) {
+	return accountList[TokenTransfer]("token1155tx", address, contractAddress, startBlock, endBlock, page, offset, sort)
+}
+
+func TokenBalance(address string, contractAddress string, tag *Tag) (*Response[string], error) {
+	if address == "" || contractAddress == "" {
+		return nil, errors.New("address and contractaddress are required")
+	}
+	params := url.Values{}
+	params.Add("module", "account")
+	params.Add("action", "tokenbalance")
+	params.Add("address", address)
+	params.Add("contractaddress", contractAddress)
+	params.Add("tag", string(tagOrLatest(tag)))
+
+	return fetchResult[string](params)
+}
+
+func accountList[T any](action string, address string, contractAddress *string, startBlock *string, endBlock *string, page *int, offset *int, sort *Sort) (*Response[[]T], error) {
+	if address == "" && contractAddress == nil {
+		return nil, errEmptyAddress
+	}
+	params := url.Values{}
+	params.Add("module", "account")
+	params.Add("action", action)
+
+	if address != "" {
+		params.Add("address", address)
+	}
+
+	if contractAddress != nil {
+		params.Add("contractaddress", *contractAddress)
+	}
+
+	blockRange(params, startBlock, endBlock)
+	pagination(params, page, offset, sort)
+
+	return fetchResult[[]T](params)
+}
+
+func tagOrLatest(tag *Tag) Tag {
+	if tag == nil {
+		return TagLatest
+	}
+	return *tag
+}
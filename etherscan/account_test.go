@@ -0,0 +1,70 @@
+package etherscan
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBalance(t *testing.T) {
+	server, query := setupMockServer(`{"status": "1", "message": "OK", "result": "40891626854930000000000"}`, http.StatusOK)
+	defer server.Close()
+
+	response, err := Balance("0xabc", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Result != "40891626854930000000000" {
+		t.Errorf("Unexpected balance %s", response.Result)
+	}
+	if query.Get("module") != "account" || query.Get("action") != "balance" || query.Get("tag") != "latest" {
+		t.Errorf("Unexpected query %v", *query)
+	}
+}
+
+func TestTxList(t *testing.T) {
+	server, query := setupMockServer(`{"status": "1", "message": "OK", "result": [{"blockNumber": "100", "hash": "0xtx", "from": "0xa", "to": "0xb", "value": "1", "isError": "0"}]}`, http.StatusOK)
+	defer server.Close()
+
+	page, offset, sort := 2, 10, SortDesc
+	response, err := TxList("0xa", nil, nil, &page, &offset, &sort)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(response.Result) != 1 || response.Result[0].Hash != "0xtx" {
+		t.Errorf("Unexpected transactions %+v", response.Result)
+	}
+	if query.Get("page") != "2" || query.Get("offset") != "10" || query.Get("sort") != "desc" {
+		t.Errorf("Unexpected query %v", *query)
+	}
+}
+
+func TestTokenTxNoTransactions(t *testing.T) {
+	server, query := setupMockServer(`{"status": "0", "message": "No transactions found", "result": []}`, http.StatusOK)
+	defer server.Close()
+
+	contract := "0xtoken"
+	response, err := TokenTx("0xa", &contract, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(response.Result) != 0 {
+		t.Errorf("Expected no transfers, got %+v", response.Result)
+	}
+	if query.Get("action") != "tokentx" || query.Get("contractaddress") != contract {
+		t.Errorf("Unexpected query %v", *query)
+	}
+}
+
+func TestBalanceMulti(t *testing.T) {
+	server, query := setupMockServer(`{"status": "1", "message": "OK", "result": [{"account": "0xa", "balance": "1"}, {"account": "0xb", "balance": "2"}]}`, http.StatusOK)
+	defer server.Close()
+
+	response, err := BalanceMulti([]string{"0xa", "0xb"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(response.Result) != 2 || !strings.Contains(query.Get("address"), "0xa,0xb") {
+		t.Errorf("Unexpected balances %+v for %v", response.Result, *query)
+	}
+}
@@ -0,0 +1,45 @@
+package etherscan
+
+import (
+	"net/url"
+)
+
+type SourceCode struct {
+	SourceCode           string `json:"SourceCode"`
+	ABI                  string `json:"ABI"`
+	ContractName         string `json:"ContractName"`
+	CompilerVersion      string `json:"CompilerVersion"`
+	OptimizationUsed     string `json:"OptimizationUsed"`
+	Runs                 string `json:"Runs"`
+	ConstructorArguments string `json:"ConstructorArguments"`
+	EVMVersion           string `json:"EVMVersion"`
+	Library              string `json:"Library"`
+	LicenseType          string `json:"LicenseType"`
+	Proxy                string `json:"Proxy"`
+	Implementation       string `json:"Implementation"`
+	SwarmSource          string `json:"SwarmSource"`
+}
+
+func GetABI(address string) (*Response[string], error) {
+	if address == "" {
+		return nil, errEmptyAddress
+	}
+	params := url.Values{}
+	params.Add("module", "contract")
+	params.Add("action", "getabi")
+	params.Add("address", address)
+
+	return fetchResult[string](params)
+}
+
+func GetSourceCode(address string) (*Response[[]SourceCode], error) {
+	if address == "" {
+		return nil, errEmptyAddress
+	}
+	params := url.Values{}
+	params.Add("module", "contract")
+	params.Add("action", "getsourcecode")
+	params.Add("address", address)
+
+	return fetchResult[[]SourceCode](params)
+}
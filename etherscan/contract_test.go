@@ -0,0 +1,22 @@
+package etherscan
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGetABI(t *testing.T) {
+	server, query := setupMockServer(`{"status": "1", "message": "OK", "result": "[{\"type\":\"function\",\"name\":\"transfer\"}]"}`, http.StatusOK)
+	defer server.Close()
+
+	response, err := GetABI("0xtoken")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Result != `[{"type":"function","name":"transfer"}]` {
+		t.Errorf("Unexpected ABI %s", response.Result)
+	}
+	if query.Get("module") != "contract" || query.Get("action") != "getabi" {
+		t.Errorf("Unexpected query %v", *query)
+	}
+}
@@ -0,0 +1,60 @@
+package etherscan
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	oklink "github.com/PaulElisha/oklink-kaiachain-sdk-go"
+)
+
+func setupMockServer(responseBody string, statusCode int) (*httptest.Server, *url.Values) {
+	query := &url.Values{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*query = r.URL.Query()
+		w.WriteHeader(statusCode)
+		w.Write([]byte(responseBody))
+	}))
+	BASE_URL = server.URL + "/"
+	return server, query
+}
+
+func TestFetchResultError(t *testing.T) {
+	server, _ := setupMockServer(`{"status": "0", "message": "NOTOK", "result": "Invalid API Key"}`, http.StatusOK)
+	defer server.Close()
+
+	_, err := Balance("0xabc", nil)
+	if err == nil || err.Error() != "API error! status: 0, message: Invalid API Key" {
+		t.Errorf("Expected API error with result detail, got %v", err)
+	}
+}
+
+func TestFetchResultHTTPError(t *testing.T) {
+	server, _ := setupMockServer(``, http.StatusTooManyRequests)
+	defer server.Close()
+
+	if _, err := Balance("0xabc", nil); err == nil {
+		t.Error("Expected HTTP error")
+	}
+}
+
+func TestFetchUsesOklinkMiddleware(t *testing.T) {
+	var key string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key = r.Header.Get("Ok-Access-Key")
+		w.Write([]byte(`{"status": "1", "message": "OK", "result": "100"}`))
+	}))
+	defer server.Close()
+	BASE_URL = server.URL + "/"
+
+	oklink.Use(oklink.Header("Ok-Access-Key", "middleware-key"))
+	defer oklink.ResetMiddleware()
+
+	if _, err := Balance("0xabc", nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if key != "middleware-key" {
+		t.Errorf("Expected the key set by oklink middleware, got %q", key)
+	}
+}
@@ -0,0 +1,64 @@
+package etherscan
+
+import (
+	"fmt"
+	"net/url"
+)
+
+type Log struct {
+	Address          string   `json:"address"`
+	Topics           []string `json:"topics"`
+	Data             string   `json:"data"`
+	BlockNumber      string   `json:"blockNumber"`
+	BlockHash        string   `json:"blockHash"`
+	TimeStamp        string   `json:"timeStamp"`
+	GasPrice         string   `json:"gasPrice"`
+	GasUsed          string   `json:"gasUsed"`
+	LogIndex         string   `json:"logIndex"`
+	TransactionHash  string   `json:"transactionHash"`
+	TransactionIndex string   `json:"transactionIndex"`
+}
+
+type LogFilter struct {
+	FromBlock *string
+	ToBlock   *string
+	Address   *string
+	// Topics holds topic0 to topic3; nil entries are not filtered on.
+	Topics [4]*string
+	// Operators holds the and/or operators between topics, keyed like Etherscan's topic0_1_opr.
+	Operators map[string]string
+	Page      *int
+	Offset    *int
+}
+
+func GetLogs(filter LogFilter) (*Response[[]Log], error) {
+	params := url.Values{}
+	params.Add("module", "logs")
+	params.Add("action", "getLogs")
+
+	if filter.FromBlock != nil {
+		params.Add("fromBlock", *filter.FromBlock)
+	}
+
+	if filter.ToBlock != nil {
+		params.Add("toBlock", *filter.ToBlock)
+	}
+
+	if filter.Address != nil {
+		params.Add("address", *filter.Address)
+	}
+
+	for i, topic := range filter.Topics {
+		if topic != nil {
+			params.Add(fmt.Sprintf("topic%d", i), *topic)
+		}
+	}
+
+	for key, operator := range filter.Operators {
+		params.Add(key, operator)
+	}
+
+	pagination(params, filter.Page, filter.Offset, nil)
+
+	return fetchResult[[]Log](params)
+}
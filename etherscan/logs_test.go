@@ -0,0 +1,28 @@
+package etherscan
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGetLogs(t *testing.T) {
+	server, query := setupMockServer(`{"status": "1", "message": "OK", "result": [{"address": "0xtoken", "topics": ["0xddf2", "0xfrom"], "data": "0x01", "blockNumber": "0x64"}]}`, http.StatusOK)
+	defer server.Close()
+
+	from, to, topic0, topic1 := "100", "200", "0xddf2", "0xfrom"
+	response, err := GetLogs(LogFilter{
+		FromBlock: &from,
+		ToBlock:   &to,
+		Topics:    [4]*string{&topic0, &topic1},
+		Operators: map[string]string{"topic0_1_opr": "and"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(response.Result) != 1 || len(response.Result[0].Topics) != 2 {
+		t.Errorf("Unexpected logs %+v", response.Result)
+	}
+	if query.Get("action") != "getLogs" || query.Get("topic1") != topic1 || query.Get("topic0_1_opr") != "and" || query.Has("topic2") {
+		t.Errorf("Unexpected query %v", *query)
+	}
+}
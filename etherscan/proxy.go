@@ -0,0 +1,182 @@
+package etherscan
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("RPC error! code: %d, message: %s", e.Code, e.Message)
+}
+
+type RPCResponse[T any] struct {
+	JsonRpc string    `json:"jsonrpc"`
+	Id      int       `json:"id"`
+	Result  T         `json:"result"`
+	Error   *RPCError `json:"error"`
+}
+
+type RPCTransaction struct {
+	BlockHash        string `json:"blockHash"`
+	BlockNumber      string `json:"blockNumber"`
+	From             string `json:"from"`
+	Gas              string `json:"gas"`
+	GasPrice         string `json:"gasPrice"`
+	Hash             string `json:"hash"`
+	Input            string `json:"input"`
+	Nonce            string `json:"nonce"`
+	To               string `json:"to"`
+	TransactionIndex string `json:"transactionIndex"`
+	Value            string `json:"value"`
+	Type             string `json:"type"`
+}
+
+type RPCReceipt struct {
+	BlockHash         string   `json:"blockHash"`
+	BlockNumber       string   `json:"blockNumber"`
+	ContractAddress   string   `json:"contractAddress"`
+	CumulativeGasUsed string   `json:"cumulativeGasUsed"`
+	EffectiveGasPrice string   `json:"effectiveGasPrice"`
+	From              string   `json:"from"`
+	GasUsed           string   `json:"gasUsed"`
+	Logs              []RPCLog `json:"logs"`
+	Status            string   `json:"status"`
+	To                string   `json:"to"`
+	TransactionHash   string   `json:"transactionHash"`
+	TransactionIndex  string   `json:"transactionIndex"`
+}
+
+type RPCLog struct {
+	Address          string   `json:"address"`
+	Topics           []string `json:"topics"`
+	Data             string   `json:"data"`
+	BlockNumber      string   `json:"blockNumber"`
+	TransactionHash  string   `json:"transactionHash"`
+	TransactionIndex string   `json:"transactionIndex"`
+	BlockHash        string   `json:"blockHash"`
+	LogIndex         string   `json:"logIndex"`
+	Removed          bool     `json:"removed"`
+}
+
+type RPCBlock struct {
+	Number        string            `json:"number"`
+	Hash          string            `json:"hash"`
+	ParentHash    string            `json:"parentHash"`
+	Timestamp     string            `json:"timestamp"`
+	Miner         string            `json:"miner"`
+	GasLimit      string            `json:"gasLimit"`
+	GasUsed       string            `json:"gasUsed"`
+	BaseFeePerGas string            `json:"baseFeePerGas"`
+	Transactions  []json.RawMessage `json:"transactions"`
+}
+
+func BlockNumber() (*RPCResponse[string], error) {
+	return proxy[string]("eth_blockNumber", url.Values{})
+}
+
+func GetBlockByNumber(tag string, full bool) (*RPCResponse[*RPCBlock], error) {
+	params := url.Values{}
+	params.Add("tag", tag)
+	params.Add("boolean", fmt.Sprint(full))
+
+	return proxy[*RPCBlock]("eth_getBlockByNumber", params)
+}
+
+func GetTransactionByHash(txHash string) (*RPCResponse[*RPCTransaction], error) {
+	params := url.Values{}
+	params.Add("txhash", txHash)
+
+	return proxy[*RPCTransaction]("eth_getTransactionByHash", params)
+}
+
+func GetTransactionReceipt(txHash string) (*RPCResponse[*RPCReceipt], error) {
+	params := url.Values{}
+	params.Add("txhash", txHash)
+
+	return proxy[*RPCReceipt]("eth_getTransactionReceipt", params)
+}
+
+func GetTransactionCount(address string, tag *Tag) (*RPCResponse[string], error) {
+	params := url.Values{}
+	params.Add("address", address)
+	params.Add("tag", string(tagOrLatest(tag)))
+
+	return proxy[string]("eth_getTransactionCount", params)
+}
+
+func Call(to string, data string, tag *Tag) (*RPCResponse[string], error) {
+	params := url.Values{}
+	params.Add("to", to)
+	params.Add("data", data)
+	params.Add("tag", string(tagOrLatest(tag)))
+
+	return proxy[string]("eth_call", params)
+}
+
+func GetCode(address string, tag *Tag) (*RPCResponse[string], error) {
+	params := url.Values{}
+	params.Add("address", address)
+	params.Add("tag", string(tagOrLatest(tag)))
+
+	return proxy[string]("eth_getCode", params)
+}
+
+func GasPrice() (*RPCResponse[string], error) {
+	return proxy[string]("eth_gasPrice", url.Values{})
+}
+
+func EstimateGas(to string, data string, value *string) (*RPCResponse[string], error) {
+	params := url.Values{}
+	params.Add("to", to)
+	params.Add("data", data)
+
+	if value != nil {
+		params.Add("value", *value)
+	}
+
+	return proxy[string]("eth_estimateGas", params)
+}
+
+func SendRawTransaction(hex string) (*RPCResponse[string], error) {
+	params := url.Values{}
+	params.Add("hex", hex)
+
+	return proxy[string]("eth_sendRawTransaction", params)
+}
+
+func proxy[T any](action string, params url.Values) (*RPCResponse[T], error) {
+	params.Set("module", "proxy")
+	params.Set("action", action)
+
+	body, err := fetch(params, checkProxy)
+	if err != nil {
+		return nil, err
+	}
+	var response RPCResponse[T]
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("error unmarshalling response: %w", err)
+	}
+	return &response, nil
+}
+
+func checkProxy(body []byte) error {
+	// Rejected requests come back in the account-style envelope instead of JSON-RPC.
+	var failure Response[json.RawMessage]
+	if json.Unmarshal(body, &failure) == nil && failure.Status == "0" {
+		return apiError(failure)
+	}
+	var response RPCResponse[json.RawMessage]
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("error unmarshalling response: %w", err)
+	}
+	if response.Error != nil {
+		return response.Error
+	}
+	return nil
+}
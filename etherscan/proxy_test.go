@@ -0,0 +1,52 @@
+package etherscan
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestBlockNumber(t *testing.T) {
+	server, query := setupMockServer(`{"jsonrpc": "2.0", "id": 83, "result": "0x8f6c2a"}`, http.StatusOK)
+	defer server.Close()
+
+	response, err := BlockNumber()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Result != "0x8f6c2a" || query.Get("module") != "proxy" || query.Get("action") != "eth_blockNumber" {
+		t.Errorf("Unexpected response %+v for %v", response, *query)
+	}
+}
+
+func TestGetTransactionReceipt(t *testing.T) {
+	server, _ := setupMockServer(`{"jsonrpc": "2.0", "id": 1, "result": {"transactionHash": "0xtx", "status": "0x1", "logs": [{"address": "0xtoken"}]}}`, http.StatusOK)
+	defer server.Close()
+
+	response, err := GetTransactionReceipt("0xtx")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Result.Status != "0x1" || len(response.Result.Logs) != 1 {
+		t.Errorf("Unexpected receipt %+v", response.Result)
+	}
+}
+
+func TestProxyRPCError(t *testing.T) {
+	server, _ := setupMockServer(`{"jsonrpc": "2.0", "id": 1, "error": {"code": -32000, "message": "nonce too low"}}`, http.StatusOK)
+	defer server.Close()
+
+	_, err := SendRawTransaction("0xf86b")
+	rpcErr, ok := err.(*RPCError)
+	if !ok || rpcErr.Code != -32000 {
+		t.Errorf("Expected RPC error, got %v", err)
+	}
+}
+
+func TestProxyEnvelopeError(t *testing.T) {
+	server, _ := setupMockServer(`{"status": "0", "message": "NOTOK", "result": "Missing Or invalid Module name"}`, http.StatusOK)
+	defer server.Close()
+
+	if _, err := GasPrice(); err == nil {
+		t.Error("Expected envelope error")
+	}
+}
@@ -0,0 +1,144 @@
+package oklink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+type TransactionQuery struct {
+	ProtocolType         *ProtocolType
+	TokenContractAddress *Address
+	Symbol               *string
+	StartBlockHeight     *string
+	EndBlockHeight       *string
+	IsFromOrTo           *string
+	Page                 *string
+	Limit                *string
+}
+
+type TokenBalancePage struct {
+	PageInfo
+	ChainFullName  string         `json:"chainFullName"`
+	ChainShortName string         `json:"chainShortName"`
+	TokenList      []TokenBalance `json:"tokenList"`
+}
+
+type TokenBalance struct {
+	Symbol               string `json:"symbol"`
+	TokenContractAddress string `json:"tokenContractAddress"`
+	TokenType            string `json:"tokenType"`
+	HoldingAmount        string `json:"holdingAmount"`
+	PriceUsd             string `json:"priceUsd"`
+	ValueUsd             string `json:"valueUsd"`
+	TokenId              string `json:"tokenId"`
+}
+
+type BatchBalancePage struct {
+	PageInfo
+	BalanceList []AddressBalance `json:"balanceList"`
+}
+
+type AddressBalance struct {
+	Address string `json:"address"`
+	Balance string `json:"balance"`
+}
+
+func (s *OKLinkSource) AddressTransactions(ctx context.Context, address Address, query TransactionQuery) (*AddressTransactionPage, error) {
+	if err := query.rejectFilters("address transaction lists", "tokenContractAddress"); err != nil {
+		return nil, err
+	}
+	response, err := AddressTransactionListContext(ctx, address, query.ProtocolType, query.Symbol, query.StartBlockHeight, query.EndBlockHeight, query.IsFromOrTo, query.Page, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return firstPage[AddressTransactionPage](response.Data)
+}
+
+func (s *OKLinkSource) AddressTokenTransfers(ctx context.Context, address Address, protocolType ProtocolType, query TransactionQuery) (*AddressTransactionPage, error) {
+	if err := query.rejectFilters("token transfer lists", "symbol"); err != nil {
+		return nil, err
+	}
+	if query.ProtocolType != nil && *query.ProtocolType != protocolType {
+		return nil, fmt.Errorf("query protocol type %s conflicts with %s", *query.ProtocolType, protocolType)
+	}
+	response, err := AddressTokenTransactionListContext(ctx, address, protocolType, query.TokenContractAddress, query.StartBlockHeight, query.EndBlockHeight, query.IsFromOrTo, query.Page, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return firstPage[AddressTransactionPage](response.Data)
+}
+
+func (s *OKLinkSource) AddressTokenBalances(ctx context.Context, address Address, protocolType ProtocolType, page *string, limit *string) (*TokenBalancePage, error) {
+	response, err := AddressTokenBalanceContext(ctx, address, protocolType, nil, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	return firstPage[TokenBalancePage](response.Data)
+}
+
+func (s *OKLinkSource) BatchBalances(ctx context.Context, addresses []Address) ([]AddressBalance, error) {
+	response, err := BatchAddressBalancesContext(ctx, addresses)
+	if err != nil {
+		return nil, err
+	}
+	page, err := firstPage[BatchBalancePage](response.Data)
+	if err != nil {
+		return nil, err
+	}
+	return page.BalanceList, nil
+}
+
+func (s *OKLinkSource) BatchTransactions(ctx context.Context, addresses []Address, query TransactionQuery) (*BatchTransactionPage, error) {
+	if err := query.rejectFilters("batch transaction lists", "protocolType", "tokenContractAddress", "symbol"); err != nil {
+		return nil, err
+	}
+	response, err := BatchAddressNormalTransactionListContext(ctx, addresses, query.StartBlockHeight, query.EndBlockHeight, query.IsFromOrTo, query.Page, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return firstPage[BatchTransactionPage](response.Data)
+}
+
+func (s *OKLinkSource) BatchTransactionDetails(ctx context.Context, txIds []string) ([]TransactionFills, error) {
+	response, err := BatchTransactionDetailsContext(ctx, txIds)
+	if err != nil {
+		return nil, err
+	}
+	return decodeData[[]TransactionFills](response.Data)
+}
+
+func (s *OKLinkSource) TokenPositions(ctx context.Context, tokenContractAddress Address, page *string, limit *string) (*TokenPositionPage, error) {
+	response, err := TokenPositionListContext(ctx, &tokenContractAddress, nil, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	return firstPage[TokenPositionPage](response.Data)
+}
+
+// rejectFilters fails when one of the named fields is set, rather than dropping a filter the endpoint lacks.
+func (q TransactionQuery) rejectFilters(call string, fields ...string) error {
+	set := map[string]bool{
+		"protocolType":         q.ProtocolType != nil,
+		"tokenContractAddress": q.TokenContractAddress != nil,
+		"symbol":               q.Symbol != nil,
+	}
+	for _, field := range fields {
+		if set[field] {
+			return fmt.Errorf("%s cannot filter by %s: %w", call, field, errors.ErrUnsupported)
+		}
+	}
+	return nil
+}
+
+// firstPage unwraps OKLink's single-element page arrays; an empty array is an empty page.
+func firstPage[T any](data any) (*T, error) {
+	pages, err := decodeData[[]T](data)
+	if err != nil {
+		return nil, err
+	}
+	if len(pages) == 0 {
+		return new(T), nil
+	}
+	return &pages[0], nil
+}
@@ -0,0 +1,95 @@
+package oklink
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+type indexerSource struct {
+	*OKLinkSource
+	txs []AddressTransaction
+}
+
+func (s *indexerSource) Name() string { return "indexer" }
+
+func (s *indexerSource) AddressTransactions(ctx context.Context, address Address, query TransactionQuery) (*AddressTransactionPage, error) {
+	return &AddressTransactionPage{PageInfo: PageInfo{Page: "1", TotalPage: "1"}, TransactionLists: s.txs}, nil
+}
+
+func TestOKLinkSourceTokenBalances(t *testing.T) {
+	mockResponse := `{"code": 0, "data": [{"page": "1", "limit": "20", "totalPage": "1", "tokenList": [{"symbol": "USDT", "tokenContractAddress": "0xusdt", "holdingAmount": "12.5", "valueUsd": "12.5"}]}], "msg": ""}`
+	server := setupMockServer(mockResponse, http.StatusOK)
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	page, err := NewOKLinkSource().AddressTokenBalances(context.Background(), "0xabc", Token20, nil, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(page.TokenList) != 1 || page.TokenList[0].HoldingAmount != "12.5" {
+		t.Errorf("Unexpected token balances %+v", page.TokenList)
+	}
+}
+
+func TestOKLinkSourceEmptyPage(t *testing.T) {
+	server := setupMockServer(`{"code": 0, "data": [], "msg": ""}`, http.StatusOK)
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	page, err := NewOKLinkSource().AddressTransactions(context.Background(), "0xabc", TransactionQuery{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(page.TransactionLists) != 0 {
+		t.Errorf("Expected an empty page, got %+v", page)
+	}
+}
+
+func TestWatcherUsesCustomSource(t *testing.T) {
+	source := &indexerSource{txs: []AddressTransaction{{TxId: "0xindexed", From: "0xaaa", To: "0xbbb", Height: "10"}}}
+	watcher := NewWatcher([]Address{"0xbbb"}, 0)
+	watcher.Source = source
+	watcher.EmitExisting = true
+	watcher.LatestHeight = func() (int64, error) { return 10, nil }
+
+	var events []WatchEvent
+	watcher.OnEvent = func(event WatchEvent) { events = append(events, event) }
+	if err := watcher.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(events) == 0 || events[0].Transaction.TxId != "0xindexed" {
+		t.Errorf("Expected event from the custom source, got %+v", events)
+	}
+}
+
+func TestOKLinkSourceTokenTransfersPassFilters(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"code": 0, "data": [], "msg": ""}`))
+	}))
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	start, end, direction := "100", "200", "to"
+	_, err := NewOKLinkSource().AddressTokenTransfers(context.Background(), "0xabc", Token20, TransactionQuery{StartBlockHeight: &start, EndBlockHeight: &end, IsFromOrTo: &direction})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if query.Get("startBlockHeight") != "100" || query.Get("endBlockHeight") != "200" || query.Get("isFromOrTo") != "to" {
+		t.Errorf("Expected height and direction filters to be sent, got %v", query)
+	}
+
+	symbol := "USDT"
+	_, err = NewOKLinkSource().AddressTokenTransfers(context.Background(), "0xabc", Token20, TransactionQuery{Symbol: &symbol})
+	if !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("Expected errors.ErrUnsupported for a symbol filter, got %v", err)
+	}
+}
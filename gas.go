@@ -0,0 +1,150 @@
+package oklink
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxGasBlocks is the most blocks block/block-list returns in one page.
+const maxGasBlocks = 100
+
+type GasSuggestion struct {
+	// Prices are in the chain's native unit, as reported by the explorer.
+	Slow      float64
+	Standard  float64
+	Fast      float64
+	BaseFee   float64
+	Height    int64
+	Blocks    int
+	UpdatedAt time.Time
+}
+
+type GasOracle struct {
+	// Blocks is how many recent blocks are sampled, capped at 100.
+	Blocks             int
+	SlowPercentile     float64
+	StandardPercentile float64
+	FastPercentile     float64
+	CacheFor           time.Duration
+	Now                func() time.Time
+
+	mu   sync.Mutex
+	last *GasSuggestion
+}
+
+func NewGasOracle(blocks int) *GasOracle {
+	return &GasOracle{
+		Blocks:             blocks,
+		SlowPercentile:     25,
+		StandardPercentile: 50,
+		FastPercentile:     90,
+		CacheFor:           15 * time.Second,
+	}
+}
+
+func (o *GasOracle) Suggest() (*GasSuggestion, error) {
+	now := o.now()
+	o.mu.Lock()
+	if o.last != nil && now.Sub(o.last.UpdatedAt) < o.CacheFor {
+		last := *o.last
+		o.mu.Unlock()
+		return &last, nil
+	}
+	o.mu.Unlock()
+
+	suggestion, err := o.fetch()
+	if err != nil {
+		return nil, err
+	}
+	suggestion.UpdatedAt = now
+
+	o.mu.Lock()
+	o.last = suggestion
+	o.mu.Unlock()
+	result := *suggestion
+	return &result, nil
+}
+
+func (o *GasOracle) fetch() (*GasSuggestion, error) {
+	blocks := o.Blocks
+	if blocks <= 0 {
+		blocks = 20
+	}
+	if blocks > maxGasBlocks {
+		blocks = maxGasBlocks
+	}
+	page, limit := "1", strconv.Itoa(blocks)
+	response, err := BlockList(nil, &page, &limit)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching recent blocks: %w", err)
+	}
+	if len(response.Data) == 0 || len(response.Data[0].BlockList) == 0 {
+		return nil, errors.New("block list returned no blocks")
+	}
+
+	list := response.Data[0].BlockList
+	suggestion := &GasSuggestion{}
+	suggestion.Height, _ = strconv.ParseInt(list[0].Height, 10, 64)
+	suggestion.BaseFee, _ = strconv.ParseFloat(list[0].BaseFeePerGas, 64)
+
+	var prices []float64
+	for _, block := range list {
+		// Empty blocks report a zero average and would drag every percentile down.
+		price, err := strconv.ParseFloat(block.GasAvgPrice, 64)
+		if err != nil || price <= 0 {
+			continue
+		}
+		prices = append(prices, price)
+	}
+	if len(prices) == 0 {
+		price, err := averageGasPrice()
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, price)
+	}
+	sort.Float64s(prices)
+
+	suggestion.Blocks = len(prices)
+	suggestion.Slow = math.Max(percentile(prices, o.SlowPercentile), suggestion.BaseFee)
+	suggestion.Standard = math.Max(percentile(prices, o.StandardPercentile), suggestion.Slow)
+	suggestion.Fast = math.Max(percentile(prices, o.FastPercentile), suggestion.Standard)
+	return suggestion, nil
+}
+
+func (o *GasOracle) now() time.Time {
+	if o.Now != nil {
+		return o.Now()
+	}
+	return time.Now()
+}
+
+func averageGasPrice() (float64, error) {
+	response, err := ChainInfo()
+	if err != nil {
+		return 0, fmt.Errorf("error fetching chain info: %w", err)
+	}
+	if len(response.Data) == 0 {
+		return 0, errors.New("chain info returned no data")
+	}
+	return strconv.ParseFloat(response.Data[0].AvgGasPrice, 64)
+}
+
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
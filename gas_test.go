@@ -0,0 +1,84 @@
+package oklink
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGasOracleSuggest(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"code": 0, "data": [{"blockList": [
+			{"height": "105", "gasAvgPrice": "30", "baseFeePerGas": "25"},
+			{"height": "104", "gasAvgPrice": "0"},
+			{"height": "103", "gasAvgPrice": "20"},
+			{"height": "102", "gasAvgPrice": "50"},
+			{"height": "101", "gasAvgPrice": "40"}
+		]}], "msg": ""}`))
+	}))
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	now := time.Now()
+	oracle := NewGasOracle(5)
+	oracle.Now = func() time.Time { return now }
+
+	suggestion, err := oracle.Suggest()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if suggestion.Height != 105 || suggestion.Blocks != 4 {
+		t.Errorf("Expected 4 priced blocks at height 105, got %+v", suggestion)
+	}
+	if suggestion.Slow != 25 || suggestion.Standard != 30 || suggestion.Fast != 50 {
+		t.Errorf("Expected 25/30/50, got %v/%v/%v", suggestion.Slow, suggestion.Standard, suggestion.Fast)
+	}
+
+	if _, err := oracle.Suggest(); err != nil || requests != 1 {
+		t.Errorf("Expected cached suggestion, got %d requests and %v", requests, err)
+	}
+}
+
+func TestGasOracleFallsBackToChainInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/blockchain/info") {
+			w.Write([]byte(`{"code": 0, "data": [{"avgGasPrice": "27"}], "msg": ""}`))
+			return
+		}
+		w.Write([]byte(`{"code": 0, "data": [{"blockList": [{"height": "9", "gasAvgPrice": ""}]}], "msg": ""}`))
+	}))
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	suggestion, err := NewGasOracle(1).Suggest()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if suggestion.Slow != 27 || suggestion.Fast != 27 {
+		t.Errorf("Expected chain average of 27, got %+v", suggestion)
+	}
+}
+
+func TestGasOracleCapsBlocks(t *testing.T) {
+	var limit string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit = r.URL.Query().Get("limit")
+		w.Write([]byte(`{"code": 0, "data": [{"blockList": [{"height": "105", "gasAvgPrice": "30"}]}], "msg": ""}`))
+	}))
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	if _, err := NewGasOracle(500).Suggest(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if limit != "100" {
+		t.Errorf("Expected limit capped at 100, got %q", limit)
+	}
+}
@@ -0,0 +1,225 @@
+package oklink
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const accessKeyHeader = "Ok-Access-Key"
+
+var ErrNoKeyAvailable = errors.New("no API key available")
+
+// DefaultBenchCodes are OKLink response codes that mean a key is rate limited, out of quota or invalid.
+var DefaultBenchCodes = map[int]bool{
+	50011: true,
+	50111: true,
+	50112: true,
+	50113: true,
+}
+
+type PoolKey struct {
+	Name string
+	Key  string
+	// RateLimit is requests per second for this key; zero means unlimited.
+	RateLimit float64
+	Burst     int
+	// Budget meters this key's own plan; nil means unmetered. An unnamed budget takes the key's name.
+	Budget *Budget
+}
+
+type KeyUsage struct {
+	Name         string
+	Requests     int64
+	Failures     int64
+	Benches      int64
+	BenchedUntil time.Time
+	LastError    string
+	Credits      []BudgetUsage
+}
+
+// KeyPool spreads requests over several API keys, skipping keys that are benched, out of budget or
+// further from their next rate-limit token than another key.
+type KeyPool struct {
+	// BenchFor is how long a key sits out after an auth or quota error.
+	BenchFor   time.Duration
+	BenchCodes map[int]bool
+	Now        func() time.Time
+
+	mu   sync.Mutex
+	keys []*pooledKey
+	next int
+}
+
+type pooledKey struct {
+	PoolKey
+	limiter      *rateLimiter
+	benchedUntil time.Time
+	requests     int64
+	failures     int64
+	benches      int64
+	lastErr      error
+}
+
+func NewKeyPool(keys ...PoolKey) *KeyPool {
+	pool := &KeyPool{BenchFor: time.Minute, BenchCodes: DefaultBenchCodes}
+	for i, key := range keys {
+		if key.Name == "" {
+			key.Name = fmt.Sprintf("key%d", i+1)
+		}
+		if key.Budget != nil && key.Budget.Name == "" {
+			key.Budget.Name = key.Name
+		}
+		pool.keys = append(pool.keys, &pooledKey{PoolKey: key, limiter: newRateLimiter(key.RateLimit, key.Burst)})
+	}
+	return pool
+}
+
+// Middleware signs each round-trip with a key from the pool. Put it inside Retry so a retried attempt
+// can move to another key.
+func (p *KeyPool) Middleware() Middleware {
+	return func(next Handler) Handler {
+		return func(req *Request) *Response {
+			req.keyBenched = false
+			ctx := req.HTTP.Context()
+			tag := budgetTag(ctx)
+			key, err := p.acquire(req.Endpoint, tag)
+			if err != nil {
+				return &Response{Err: err}
+			}
+			wait, err := key.limiter.Wait(ctx)
+			if err != nil {
+				key.refund(req.Endpoint, tag)
+				return &Response{Err: err}
+			}
+			currentMetrics().observeLimiterWait(wait)
+
+			req.HTTP.Header.Set(accessKeyHeader, key.Key)
+			resp := next(req)
+			if resp.StatusCode == 0 {
+				key.refund(req.Endpoint, tag)
+			}
+			req.keyBenched = p.release(key, resp)
+			return resp
+		}
+	}
+}
+
+// Usage reports request counts, bench state and credit usage for every key.
+func (p *KeyPool) Usage() []KeyUsage {
+	p.mu.Lock()
+	usage := make([]KeyUsage, 0, len(p.keys))
+	budgets := make([]*Budget, 0, len(p.keys))
+	for _, key := range p.keys {
+		entry := KeyUsage{
+			Name:     key.Name,
+			Requests: key.requests,
+			Failures: key.failures,
+			Benches:  key.benches,
+		}
+		if key.benchedUntil.After(p.now()) {
+			entry.BenchedUntil = key.benchedUntil
+		}
+		if key.lastErr != nil {
+			entry.LastError = key.lastErr.Error()
+		}
+		usage = append(usage, entry)
+		budgets = append(budgets, key.Budget)
+	}
+	p.mu.Unlock()
+	// Budgets may read their Store, so credits are collected without holding the pool lock.
+	for i, budget := range budgets {
+		if budget != nil {
+			usage[i].Credits = budget.Usage()
+		}
+	}
+	return usage
+}
+
+// acquire picks the next usable key in round-robin order, preferring one with a rate-limit token free now,
+// and reserves the call against its budget. Budgets may read their Store, so they are charged without
+// holding the pool lock.
+func (p *KeyPool) acquire(endpoint string, tag string) (*pooledKey, error) {
+	type candidate struct {
+		key   *pooledKey
+		index int
+		delay time.Duration
+	}
+	p.mu.Lock()
+	if len(p.keys) == 0 {
+		p.mu.Unlock()
+		return nil, ErrNoKeyAvailable
+	}
+	now := p.now()
+	var candidates []candidate
+	var reasons []error
+	for i := range p.keys {
+		index := (p.next + i) % len(p.keys)
+		key := p.keys[index]
+		if now.Before(key.benchedUntil) {
+			reasons = append(reasons, fmt.Errorf("%s benched until %s", key.Name, key.benchedUntil.Format(time.RFC3339)))
+			continue
+		}
+		candidates = append(candidates, candidate{key: key, index: index, delay: key.limiter.delay()})
+	}
+	p.mu.Unlock()
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].delay < candidates[j].delay })
+	for _, c := range candidates {
+		if c.key.Budget != nil {
+			if err := c.key.Budget.Reserve(endpoint, tag); err != nil {
+				reasons = append(reasons, fmt.Errorf("%s: %w", c.key.Name, err))
+				continue
+			}
+		}
+		p.mu.Lock()
+		p.next = c.index + 1
+		c.key.requests++
+		p.mu.Unlock()
+		return c.key, nil
+	}
+	return nil, fmt.Errorf("%w: %w", ErrNoKeyAvailable, errors.Join(reasons...))
+}
+
+// refund returns the credits acquire reserved for a call that never reached OKLink.
+func (k *pooledKey) refund(endpoint string, tag string) {
+	if k.Budget != nil {
+		k.Budget.Refund(endpoint, tag)
+	}
+}
+
+// release records the outcome of a round-trip on key and reports whether the key was benched.
+func (p *KeyPool) release(key *pooledKey, resp *Response) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if resp.Err == nil {
+		return false
+	}
+	key.failures++
+	key.lastErr = resp.Err
+	if !p.benchable(resp) {
+		return false
+	}
+	key.benches++
+	key.benchedUntil = p.now().Add(p.BenchFor)
+	return true
+}
+
+func (p *KeyPool) benchable(resp *Response) bool {
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+		return true
+	}
+	var apiErr *APIError
+	return errors.As(resp.Err, &apiErr) && p.BenchCodes[apiErr.Code]
+}
+
+func (p *KeyPool) now() time.Time {
+	if p.Now != nil {
+		return p.Now()
+	}
+	return time.Now()
+}
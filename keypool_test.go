@@ -0,0 +1,204 @@
+package oklink
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func setupKeyServer(t *testing.T, respond func(key string) string) *[]string {
+	var mu sync.Mutex
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Ok-Access-Key")
+		mu.Lock()
+		keys = append(keys, key)
+		mu.Unlock()
+		w.Write([]byte(respond(key)))
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(ResetMiddleware)
+	BASE_URL = server.URL + "/"
+	return &keys
+}
+
+func okResponse(string) string {
+	return `{"code": 0, "data": {"balance": "1"}, "msg": ""}`
+}
+
+func TestKeyPoolRotatesKeys(t *testing.T) {
+	keys := setupKeyServer(t, okResponse)
+	pool := NewKeyPool(PoolKey{Key: "a"}, PoolKey{Key: "b"})
+	Use(pool.Middleware())
+
+	for i := 0; i < 4; i++ {
+		if _, err := AddressInfo("0xpool"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if got := *keys; len(got) != 4 || got[0] != "a" || got[1] != "b" || got[2] != "a" || got[3] != "b" {
+		t.Errorf("Expected keys to alternate, got %v", got)
+	}
+	usage := pool.Usage()
+	if usage[0].Name != "key1" || usage[0].Requests != 2 || usage[1].Requests != 2 {
+		t.Errorf("Unexpected usage %+v", usage)
+	}
+}
+
+func TestKeyPoolBenchesFailingKeys(t *testing.T) {
+	keys := setupKeyServer(t, func(key string) string {
+		if key == "revoked" {
+			return `{"code": 50111, "data": null, "msg": "Invalid OK-ACCESS-KEY"}`
+		}
+		return okResponse(key)
+	})
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	pool := NewKeyPool(PoolKey{Name: "revoked", Key: "revoked"}, PoolKey{Name: "good", Key: "good"})
+	pool.Now = func() time.Time { return now }
+	Use(pool.Middleware())
+
+	if _, err := AddressInfo("0xpool"); err == nil {
+		t.Fatal("Expected the revoked key to fail")
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := AddressInfo("0xpool"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if got := *keys; got[1] != "good" || got[2] != "good" || got[3] != "good" {
+		t.Errorf("Expected the benched key to be skipped, got %v", got)
+	}
+	usage := pool.Usage()
+	if usage[0].Benches != 1 || usage[0].Failures != 1 || usage[0].BenchedUntil.IsZero() || usage[0].LastError == "" {
+		t.Errorf("Unexpected usage for the revoked key %+v", usage[0])
+	}
+
+	now = now.Add(2 * time.Minute)
+	AddressInfo("0xpool")
+	if got := *keys; got[len(got)-1] != "revoked" {
+		t.Errorf("Expected the key to return after its bench, got %v", got)
+	}
+}
+
+func TestRetryMovesToAnotherKey(t *testing.T) {
+	keys := setupKeyServer(t, func(key string) string {
+		if key == "limited" {
+			return `{"code": 50011, "data": null, "msg": "Too Many Requests"}`
+		}
+		return okResponse(key)
+	})
+	pool := NewKeyPool(PoolKey{Key: "limited"}, PoolKey{Key: "good"})
+	Use(Retry(3, time.Millisecond), pool.Middleware())
+
+	if _, err := AddressInfo("0xpool"); err != nil {
+		t.Fatalf("Expected the retry to succeed on another key, got %v", err)
+	}
+	if got := *keys; len(got) != 2 || got[0] != "limited" || got[1] != "good" {
+		t.Errorf("Expected the retry to use the other key, got %v", got)
+	}
+}
+
+func TestRetryMovesPastRevokedKey(t *testing.T) {
+	keys := setupKeyServer(t, func(key string) string {
+		if key == "revoked" {
+			return `{"code": 50111, "data": null, "msg": "Invalid OK-ACCESS-KEY"}`
+		}
+		return okResponse(key)
+	})
+	pool := NewKeyPool(PoolKey{Key: "revoked"}, PoolKey{Key: "good"})
+	Use(Retry(3, time.Millisecond), pool.Middleware())
+
+	if _, err := AddressInfo("0xpool"); err != nil {
+		t.Fatalf("Expected the retry to succeed on the healthy key, got %v", err)
+	}
+	if got := *keys; len(got) != 2 || got[0] != "revoked" || got[1] != "good" {
+		t.Errorf("Expected the retry to use the other key, got %v", got)
+	}
+}
+
+func TestKeyPoolRespectsKeyBudgets(t *testing.T) {
+	keys := setupKeyServer(t, okResponse)
+	small := NewBudget(nil)
+	small.Limits = []BudgetLimit{{Period: Daily, Hard: 1}}
+	large := NewBudget(nil)
+	large.Limits = []BudgetLimit{{Period: Daily, Hard: 2}}
+	pool := NewKeyPool(PoolKey{Key: "small", Budget: small}, PoolKey{Key: "large", Budget: large})
+	Use(pool.Middleware())
+
+	for i := 0; i < 3; i++ {
+		if _, err := AddressInfo("0xpool"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	_, err := AddressInfo("0xpool")
+	if !errors.Is(err, ErrNoKeyAvailable) || !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("Expected every key to be out of budget, got %v", err)
+	}
+	if got := *keys; len(got) != 3 || got[2] != "large" {
+		t.Errorf("Expected the third call to use the larger budget, got %v", got)
+	}
+	if credits := pool.Usage()[1].Credits; len(credits) == 0 {
+		t.Error("Expected per-key credit usage")
+	}
+}
+
+func TestKeyPoolReservesBudgetAtomically(t *testing.T) {
+	keys := setupKeyServer(t, okResponse)
+	budget := NewBudget(NewMemoryCache(0))
+	budget.Limits = []BudgetLimit{{Period: Daily, Hard: 3}}
+	pool := NewKeyPool(PoolKey{Key: "only", Budget: budget})
+	Use(pool.Middleware())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			AddressInfo(Address("0xpool" + string(rune('a'+i))))
+		}(i)
+	}
+	wg.Wait()
+	if len(*keys) != 3 {
+		t.Errorf("Expected the budget to admit exactly 3 calls, got %d", len(*keys))
+	}
+}
+
+func TestKeyPoolRefundsUnsentCalls(t *testing.T) {
+	setupKeyServer(t, okResponse)
+	budget := NewBudget(nil)
+	budget.Limits = []BudgetLimit{{Period: Daily, Hard: 1}}
+	pool := NewKeyPool(PoolKey{Key: "only", Budget: budget})
+	Use(RequestHook(func(req *Request) error {
+		req.HTTP.URL.Host = "127.0.0.1:1"
+		return nil
+	}), pool.Middleware())
+
+	for i := 0; i < 2; i++ {
+		if _, err := AddressInfo("0xpool"); err == nil || errors.Is(err, ErrNoKeyAvailable) {
+			t.Fatalf("Expected a transport error, got %v", err)
+		}
+	}
+	for _, entry := range pool.Usage()[0].Credits {
+		if entry.Used != 0 {
+			t.Errorf("Expected unsent calls to be refunded, got %+v", entry)
+		}
+	}
+}
+
+func TestKeyPoolPrefersKeyWithFreeToken(t *testing.T) {
+	keys := setupKeyServer(t, okResponse)
+	pool := NewKeyPool(PoolKey{Key: "slow", RateLimit: 0.001, Burst: 1}, PoolKey{Key: "fast"})
+	Use(pool.Middleware())
+
+	for i := 0; i < 3; i++ {
+		if _, err := AddressInfo("0xpool"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if got := *keys; got[0] != "slow" || got[1] != "fast" || got[2] != "fast" {
+		t.Errorf("Expected the rate-limited key to be skipped while it waits, got %v", got)
+	}
+}
@@ -0,0 +1,133 @@
+package oklink
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Loader[K comparable, V any] struct {
+	Wait     time.Duration
+	MaxBatch int
+	// Fetch returns results keyed by the requested keys; keys missing from the map are not found.
+	Fetch func(keys []K) (map[K]V, error)
+
+	mu    sync.Mutex
+	batch *loaderBatch[K, V]
+}
+
+type loaderBatch[K comparable, V any] struct {
+	keys    []K
+	index   map[K]bool
+	results map[K]V
+	err     error
+	done    chan struct{}
+}
+
+const (
+	batchBalanceLimit     = 100
+	batchTransactionLimit = 20
+)
+
+func NewLoader[K comparable, V any](maxBatch int, wait time.Duration, fetch func(keys []K) (map[K]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{Wait: wait, MaxBatch: maxBatch, Fetch: fetch}
+}
+
+func (l *Loader[K, V]) Load(key K) (V, error) {
+	l.mu.Lock()
+	batch := l.batch
+	if batch == nil {
+		batch = &loaderBatch[K, V]{index: map[K]bool{}, done: make(chan struct{})}
+		l.batch = batch
+		time.AfterFunc(l.Wait, func() { l.dispatch(batch) })
+	}
+	if !batch.index[key] {
+		batch.index[key] = true
+		batch.keys = append(batch.keys, key)
+	}
+	full := l.MaxBatch > 0 && len(batch.keys) >= l.MaxBatch
+	l.mu.Unlock()
+
+	if full {
+		l.dispatch(batch)
+	}
+	<-batch.done
+
+	var zero V
+	if batch.err != nil {
+		return zero, batch.err
+	}
+	value, ok := batch.results[key]
+	if !ok {
+		return zero, fmt.Errorf("%v: %w", key, ErrNotFound)
+	}
+	return value, nil
+}
+
+func (l *Loader[K, V]) dispatch(batch *loaderBatch[K, V]) {
+	l.mu.Lock()
+	// The timer and a full batch can both dispatch; only the first one runs.
+	if l.batch != batch {
+		l.mu.Unlock()
+		return
+	}
+	l.batch = nil
+	l.mu.Unlock()
+
+	defer close(batch.done)
+	defer func() {
+		// Fetch may run on a timer goroutine, so a panic is handed to the waiters instead of crashing.
+		if r := recover(); r != nil {
+			batch.results, batch.err = nil, fmt.Errorf("error fetching batch: panic: %v", r)
+		}
+	}()
+	batch.results, batch.err = l.Fetch(batch.keys)
+}
+
+func NewBalanceLoader(source DataSource, wait time.Duration) *Loader[Address, AddressBalance] {
+	if source == nil {
+		source = NewOKLinkSource()
+	}
+	return NewLoader(batchBalanceLimit, wait, func(addresses []Address) (map[Address]AddressBalance, error) {
+		balances, err := source.BatchBalances(context.Background(), addresses)
+		if err != nil {
+			return nil, err
+		}
+		byAddress := map[string]AddressBalance{}
+		for _, balance := range balances {
+			byAddress[strings.ToLower(balance.Address)] = balance
+		}
+		results := map[Address]AddressBalance{}
+		for _, address := range addresses {
+			if balance, ok := byAddress[strings.ToLower(string(address))]; ok {
+				results[address] = balance
+			}
+		}
+		return results, nil
+	})
+}
+
+func NewTransactionLoader(source DataSource, wait time.Duration) *Loader[string, TransactionFills] {
+	if source == nil {
+		source = NewOKLinkSource()
+	}
+	return NewLoader(batchTransactionLimit, wait, func(txIds []string) (map[string]TransactionFills, error) {
+		txs, err := source.BatchTransactionDetails(context.Background(), txIds)
+		if err != nil {
+			return nil, err
+		}
+		byId := map[string]TransactionFills{}
+		for _, tx := range txs {
+			byId[strings.ToLower(tx.TxId)] = tx
+		}
+		results := map[string]TransactionFills{}
+		for _, txId := range txIds {
+			if tx, ok := byId[strings.ToLower(txId)]; ok {
+				results[txId] = tx
+			}
+		}
+		return results, nil
+	})
+}
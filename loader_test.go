@@ -0,0 +1,129 @@
+package oklink
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTransactionLoaderBatchesWithinLimit(t *testing.T) {
+	var batches atomic.Int64
+	var largest atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		batches.Add(1)
+		txIds := strings.Split(r.URL.Query().Get("txIds"), ",")
+		if int64(len(txIds)) > largest.Load() {
+			largest.Store(int64(len(txIds)))
+		}
+		var entries []string
+		for _, txId := range txIds {
+			if txId != "0xmissing" {
+				entries = append(entries, fmt.Sprintf(`{"txid": "%s", "state": "success"}`, strings.ToUpper(txId)))
+			}
+		}
+		fmt.Fprintf(w, `{"code": 0, "data": [%s], "msg": ""}`, strings.Join(entries, ","))
+	}))
+	defer server.Close()
+	BASE_URL = server.URL + "/"
+
+	loader := NewTransactionLoader(nil, 20*time.Millisecond)
+	var wg sync.WaitGroup
+	errs := make([]error, 26)
+	for i := range errs {
+		txId := fmt.Sprintf("0xtx%d", i)
+		if i == 25 {
+			txId = "0xmissing"
+		}
+		wg.Add(1)
+		go func(i int, txId string) {
+			defer wg.Done()
+			tx, err := loader.Load(txId)
+			if err == nil && !strings.EqualFold(tx.TxId, txId) {
+				err = fmt.Errorf("got %s for %s", tx.TxId, txId)
+			}
+			errs[i] = err
+		}(i, txId)
+	}
+	wg.Wait()
+
+	for i, err := range errs[:25] {
+		if err != nil {
+			t.Errorf("Load %d: expected no error, got %v", i, err)
+		}
+	}
+	if !errors.Is(errs[25], ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for the missing transaction, got %v", errs[25])
+	}
+	if batches.Load() != 2 || largest.Load() > batchTransactionLimit {
+		t.Errorf("Expected 2 batches of at most %d, got %d batches (largest %d)", batchTransactionLimit, batches.Load(), largest.Load())
+	}
+}
+
+func TestLoaderSharesBatchErrorAndDeduplicates(t *testing.T) {
+	var calls [][]string
+	var mu sync.Mutex
+	loader := NewLoader(10, 10*time.Millisecond, func(keys []string) (map[string]int, error) {
+		mu.Lock()
+		calls = append(calls, keys)
+		mu.Unlock()
+		return nil, errors.New("quota exceeded")
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := loader.Load("same"); err == nil || err.Error() != "quota exceeded" {
+				t.Errorf("Expected the batch error, got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(calls) != 1 || len(calls[0]) != 1 {
+		t.Errorf("Expected one deduplicated batch, got %v", calls)
+	}
+}
+
+func TestLoaderReportsFetchPanic(t *testing.T) {
+	loader := NewLoader(2, time.Millisecond, func(keys []string) (map[string]int, error) {
+		panic("boom")
+	})
+
+	var wg sync.WaitGroup
+	for _, key := range []string{"a", "b"} {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			if _, err := loader.Load(key); err == nil || !strings.Contains(err.Error(), "boom") {
+				t.Errorf("Expected the panic as an error, got %v", err)
+			}
+		}(key)
+	}
+	wg.Wait()
+}
+
+func TestBatchTransactionDetailsSendsChainShortName(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"code": 0, "data": [], "msg": ""}`))
+	}))
+	defer server.Close()
+	BASE_URL = server.URL + "/"
+
+	if _, err := BatchTransactionDetails([]string{"0xa", "0xb"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if query.Get("chainShortName") != CHAIN_SHORTNAME || query.Get("txIds") != "0xa,0xb" {
+		t.Errorf("Unexpected query %v", query)
+	}
+}
@@ -0,0 +1,198 @@
+package oklink
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	addressPattern     = regexp.MustCompile(`\b0x[0-9a-fA-F]{40}\b`)
+	secretParamPattern = regexp.MustCompile(`(?i)\b(apikey|api_key|access_key)=[^&\s"]+`)
+	sensitiveHeaders   = []string{"Ok-Access-Key", "Authorization"}
+	sensitiveParams    = map[string]bool{"apikey": true, "api_key": true, "access_key": true}
+)
+
+// RequestLogger emits a structured event before and after every round-trip.
+type RequestLogger struct {
+	Logger *slog.Logger
+	// RequestLevel and ResponseLevel apply to successful round-trips; failed ones are logged at ErrorLevel.
+	RequestLevel  slog.Level
+	ResponseLevel slog.Level
+	ErrorLevel    slog.Level
+	// HashAddresses replaces every address in params and errors with a salted hash, so logs can be
+	// correlated without naming the account.
+	HashAddresses bool
+	HashSalt      string
+	// BodySampleRate is the fraction of round-trips whose body is kept and logged if decoding it fails.
+	BodySampleRate float64
+	MaxBodyBytes   int
+	Random         func() float64
+}
+
+var activeLogger atomic.Pointer[RequestLogger]
+
+func NewRequestLogger(logger *slog.Logger) *RequestLogger {
+	return &RequestLogger{
+		Logger:        logger,
+		RequestLevel:  slog.LevelDebug,
+		ResponseLevel: slog.LevelDebug,
+		ErrorLevel:    slog.LevelWarn,
+		MaxBodyBytes:  2048,
+	}
+}
+
+// EnableLogging logs every round-trip through logger; nil turns logging off again.
+func EnableLogging(logger *RequestLogger) {
+	activeLogger.Store(logger)
+}
+
+func currentLogger() *RequestLogger {
+	return activeLogger.Load()
+}
+
+func (l *RequestLogger) logger() *slog.Logger {
+	if l.Logger != nil {
+		return l.Logger
+	}
+	return slog.Default()
+}
+
+func (l *RequestLogger) sampleBody() bool {
+	if l == nil || l.BodySampleRate <= 0 {
+		return false
+	}
+	random := rand.Float64
+	if l.Random != nil {
+		random = l.Random
+	}
+	return random() < l.BodySampleRate
+}
+
+func (l *RequestLogger) logRequest(ctx context.Context, req *Request) {
+	if l == nil || !l.logger().Enabled(ctx, l.RequestLevel) {
+		return
+	}
+	l.logger().LogAttrs(ctx, l.RequestLevel, "oklink request",
+		slog.String("endpoint", req.Endpoint),
+		slog.String("method", req.Method),
+		slog.Int("attempt", req.Attempt),
+		l.paramsAttr(req.Params),
+		l.headersAttr(req.HTTP.Header))
+}
+
+// logResponse logs the outcome of a round-trip; body holds the raw response when it was sampled.
+func (l *RequestLogger) logResponse(ctx context.Context, req *Request, resp *Response, body *bytes.Buffer) {
+	if l == nil {
+		return
+	}
+	level := l.ResponseLevel
+	if resp.Err != nil {
+		level = l.ErrorLevel
+	}
+	if !l.logger().Enabled(ctx, level) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("endpoint", req.Endpoint),
+		slog.Int("attempt", req.Attempt),
+		slog.Int("status", resp.StatusCode),
+		slog.Duration("latency", resp.Latency),
+	}
+	var apiErr *APIError
+	switch {
+	case resp.Err == nil:
+		attrs = append(attrs, slog.Int("code", 0))
+	case errors.As(resp.Err, &apiErr):
+		attrs = append(attrs, slog.Int("code", apiErr.Code), slog.String("error", l.redact(apiErr.Msg)))
+	default:
+		attrs = append(attrs, slog.String("error", l.redact(resp.Err.Error())))
+		// A failure after a 200 means the body itself could not be read or decoded.
+		if body != nil && resp.StatusCode == http.StatusOK {
+			attrs = append(attrs, slog.String("body", l.redact(truncate(body.String(), l.MaxBodyBytes))))
+		}
+	}
+	l.logger().LogAttrs(ctx, level, "oklink response", attrs...)
+}
+
+func (l *RequestLogger) paramsAttr(params url.Values) slog.Attr {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	attrs := make([]any, 0, len(keys))
+	for _, key := range keys {
+		value := strings.Join(params[key], ",")
+		if sensitiveParams[strings.ToLower(key)] {
+			value = redacted
+		}
+		attrs = append(attrs, slog.String(key, l.redact(value)))
+	}
+	return slog.Group("params", attrs...)
+}
+
+func (l *RequestLogger) headersAttr(header http.Header) slog.Attr {
+	var attrs []any
+	for _, name := range sensitiveHeaders {
+		if header.Get(name) != "" {
+			attrs = append(attrs, slog.String(name, redacted))
+		}
+	}
+	return slog.Group("headers", attrs...)
+}
+
+// redact strips API keys from URLs in text and hashes addresses when HashAddresses is set.
+func (l *RequestLogger) redact(text string) string {
+	text = secretParamPattern.ReplaceAllString(text, "${1}="+redacted)
+	if !l.HashAddresses {
+		return text
+	}
+	return addressPattern.ReplaceAllStringFunc(text, l.hashAddress)
+}
+
+func (l *RequestLogger) hashAddress(address string) string {
+	sum := sha256.Sum256([]byte(l.HashSalt + strings.ToLower(address)))
+	return "addr:" + hex.EncodeToString(sum[:8])
+}
+
+func truncate(text string, max int) string {
+	if max <= 0 || len(text) <= max {
+		return text
+	}
+	return text[:max] + "..."
+}
+
+// logAttempt wraps one round-trip with request and response events. send gets the buffer to copy the
+// body into, which is a pooled one when the body is sampled and the caller did not ask for it.
+func logAttempt(ctx context.Context, req *Request, buf *bytes.Buffer, send func(buf *bytes.Buffer) *Response) *Response {
+	logger := currentLogger()
+	if logger == nil {
+		return send(buf)
+	}
+	logger.logRequest(ctx, req)
+	var sampled *bytes.Buffer
+	if logger.sampleBody() {
+		sampled = buf
+		if sampled == nil {
+			sampled = getBuffer()
+			defer putBuffer(sampled)
+		}
+		buf = sampled
+	}
+	resp := send(buf)
+	logger.logResponse(ctx, req, resp, sampled)
+	return resp
+}
@@ -0,0 +1,124 @@
+package oklink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func setupLogging(t *testing.T, configure func(*RequestLogger)) *bytes.Buffer {
+	var output bytes.Buffer
+	logger := NewRequestLogger(slog.New(slog.NewJSONHandler(&output, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	if configure != nil {
+		configure(logger)
+	}
+	EnableLogging(logger)
+	t.Cleanup(func() { EnableLogging(nil) })
+	return &output
+}
+
+func logEvents(t *testing.T, output *bytes.Buffer) []map[string]any {
+	var events []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		var event map[string]any
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("Expected JSON log line, got %q", line)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestLoggingRequestAndResponse(t *testing.T) {
+	server := setupMockServer(`{"code": 0, "data": {"balance": "1"}, "msg": ""}`, http.StatusOK)
+	defer server.Close()
+	BASE_URL = server.URL + "/"
+	output := setupLogging(t, nil)
+	defer ResetMiddleware()
+	Use(Header("Ok-Access-Key", "super-secret"))
+
+	address := Address("0x" + strings.Repeat("ab", 20))
+	if _, err := AddressInfo(address); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if strings.Contains(output.String(), "super-secret") {
+		t.Fatalf("Expected the API key to be redacted, got %s", output.String())
+	}
+
+	events := logEvents(t, output)
+	if len(events) != 2 {
+		t.Fatalf("Expected request and response events, got %d", len(events))
+	}
+	request, response := events[0], events[1]
+	if request["msg"] != "oklink request" || request["level"] != "DEBUG" || request["endpoint"] != "address/address-summary" {
+		t.Errorf("Unexpected request event %v", request)
+	}
+	if params := request["params"].(map[string]any); params["address"] != string(address) {
+		t.Errorf("Expected the plain address without hashing, got %v", params)
+	}
+	if headers := request["headers"].(map[string]any); headers["Ok-Access-Key"] != redacted {
+		t.Errorf("Expected a redacted key header, got %v", headers)
+	}
+	if response["msg"] != "oklink response" || response["status"] != float64(200) || response["code"] != float64(0) {
+		t.Errorf("Unexpected response event %v", response)
+	}
+}
+
+func TestLoggingHashesAddressesAndRedactsKeys(t *testing.T) {
+	logger := NewRequestLogger(nil)
+	logger.HashAddresses = true
+	logger.HashSalt = "salt"
+
+	address := "0x" + strings.Repeat("Cd", 20)
+	text := logger.redact(`Get "https://example.com/api?apikey=abc123&address=` + address + `": timeout`)
+	if strings.Contains(text, "abc123") || strings.Contains(text, address) {
+		t.Fatalf("Expected key and address to be removed, got %s", text)
+	}
+	if !strings.Contains(text, "apikey="+redacted) || !strings.Contains(text, logger.hashAddress(strings.ToLower(address))) {
+		t.Errorf("Expected redacted key and stable address hash, got %s", text)
+	}
+	txId := "0x" + strings.Repeat("ab", 32)
+	if logger.redact(txId) != txId {
+		t.Errorf("Expected transaction hashes to be left alone")
+	}
+}
+
+func TestLoggingSamplesBodyOnDecodeFailure(t *testing.T) {
+	server := setupMockServer(`{"code": 0, "data": {"balance": 12}}`, http.StatusOK)
+	defer server.Close()
+	BASE_URL = server.URL + "/"
+	output := setupLogging(t, func(logger *RequestLogger) {
+		logger.BodySampleRate = 0.5
+		logger.Random = func() float64 { return 0.1 }
+		logger.MaxBodyBytes = 16
+	})
+
+	if _, err := AddressInfo("0xbroken"); err == nil {
+		t.Fatal("Expected a decode error")
+	}
+	events := logEvents(t, output)
+	response := events[len(events)-1]
+	if response["level"] != "WARN" || response["body"] != `{"code": 0, "dat...` {
+		t.Errorf("Expected a truncated body sample at warn level, got %v", response)
+	}
+}
+
+func TestLoggingDisabledByDefault(t *testing.T) {
+	if currentLogger() != nil {
+		t.Fatal("Expected no logger by default")
+	}
+	resp := logAttempt(context.Background(), &Request{}, nil, func(buf *bytes.Buffer) *Response {
+		if buf != nil {
+			t.Error("Expected no buffer without sampling")
+		}
+		return &Response{Err: errors.New("boom")}
+	})
+	if resp.Err == nil {
+		t.Error("Expected the response to pass through")
+	}
+}
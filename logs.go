@@ -0,0 +1,57 @@
+package oklink
+
+import (
+	"fmt"
+	"net/url"
+)
+
+type Log struct {
+	Height          string   `json:"height"`
+	Address         string   `json:"address"`
+	Topics          []string `json:"topics"`
+	Data            string   `json:"data"`
+	MethodId        string   `json:"methodId"`
+	BlockHash       string   `json:"blockHash"`
+	TransactionTime string   `json:"transactionTime"`
+	LogIndex        string   `json:"logIndex"`
+	TxId            string   `json:"txId"`
+}
+
+func LogsByBlockAndAddress(startBlockHeight string, endBlockHeight string, address Address) (*ApiResponse[[]Log], error) {
+	params := url.Values{}
+	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
+	params.Add("startBlockHeight", startBlockHeight)
+	params.Add("endBlockHeight", endBlockHeight)
+	params.Add("address", string(address))
+
+	url := fmt.Sprintf("%sapi/v5/explorer/log/by-block-and-address?%s", BASE_URL, params.Encode())
+	return fetchApi[[]Log](url)
+}
+
+func LogsByAddressAndTopic(address Address, topic0 string) (*ApiResponse[[]Log], error) {
+	params := url.Values{}
+	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
+	params.Add("address", string(address))
+	params.Add("topic0", topic0)
+
+	url := fmt.Sprintf("%sapi/v5/explorer/log/by-address-and-topic?%s", BASE_URL, params.Encode())
+	return fetchApi[[]Log](url)
+}
+
+func LogsByAddress(address Address) (*ApiResponse[[]Log], error) {
+	params := url.Values{}
+	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
+	params.Add("address", string(address))
+
+	url := fmt.Sprintf("%sapi/v5/explorer/log/by-address?%s", BASE_URL, params.Encode())
+	return fetchApi[[]Log](url)
+}
+
+func LogsByTransaction(txId string) (*ApiResponse[[]Log], error) {
+	params := url.Values{}
+	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
+	params.Add("txId", txId)
+
+	url := fmt.Sprintf("%sapi/v5/explorer/log/by-transaction?%s", BASE_URL, params.Encode())
+	return fetchApi[[]Log](url)
+}
@@ -0,0 +1,46 @@
+package oklink
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLogsByAddressAndTopic(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("topic0")
+		w.Write([]byte(`{"code": 0, "data": [{"height": "100", "address": "0xtoken", "topics": ["0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"], "data": "0x", "txId": "0xtx"}], "msg": ""}`))
+	}))
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	response, err := LogsByAddressAndTopic("0xtoken", "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if query != "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef" {
+		t.Errorf("Expected topic0 param, got %s", query)
+	}
+	if len(response.Data) != 1 || response.Data[0].TxId != "0xtx" {
+		t.Errorf("Expected one log for 0xtx, got %+v", response.Data)
+	}
+}
+
+func TestDecodeLogsSkipsUnknownEvents(t *testing.T) {
+	abi, _ := ParseABI([]byte(testEventABI))
+
+	logs := []Log{
+		{Topics: []string{"0x" + abiWord(42)}},
+		{Topics: []string{"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", "0x" + abiWord(1), "0x" + abiWord(2)}, Data: "0x" + abiWord(5)},
+	}
+	decoded, err := abi.DecodeLogs(logs)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(decoded) != 1 || decoded[0].Name != "Transfer" {
+		t.Errorf("Expected only the Transfer log, got %+v", decoded)
+	}
+}
@@ -0,0 +1,138 @@
+package oklink
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsNamespace = "oklink"
+
+// Metrics holds the SDK's Prometheus collectors. It is itself a prometheus.Collector.
+type Metrics struct {
+	requests    *prometheus.CounterVec
+	latency     *prometheus.HistogramVec
+	retries     *prometheus.CounterVec
+	limiterWait prometheus.Histogram
+	cache       *prometheus.CounterVec
+	budget      *prometheus.GaugeVec
+}
+
+var activeMetrics atomic.Pointer[Metrics]
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "requests_total",
+			Help:      "Round-trips to the OKLink API by endpoint, HTTP status (0 without a response) and OKLink response code.",
+		}, []string{"endpoint", "status", "code"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "request_duration_seconds",
+			Help:      "Latency of round-trips to the OKLink API.",
+			Buckets:   prometheus.ExponentialBuckets(0.025, 2, 10),
+		}, []string{"endpoint"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "retries_total",
+			Help:      "Round-trips that repeated a failed attempt.",
+		}, []string{"endpoint"}),
+		limiterWait: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "rate_limiter_wait_seconds",
+			Help:      "Time requests spent waiting for the client-side rate limiter.",
+			Buckets:   prometheus.ExponentialBuckets(0.005, 2, 12),
+		}),
+		cache: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "cache_lookups_total",
+			Help:      "Response cache lookups by endpoint and result (hit, miss or error).",
+		}, []string{"endpoint", "result"}),
+		budget: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "budget_remaining",
+			Help:      "Remaining request budget by budget name.",
+		}, []string{"budget"}),
+	}
+}
+
+// EnableMetrics registers a new set of collectors on registry and starts recording into them.
+func EnableMetrics(registry prometheus.Registerer) (*Metrics, error) {
+	metrics := NewMetrics()
+	if err := registry.Register(metrics); err != nil {
+		return nil, fmt.Errorf("error registering metrics: %w", err)
+	}
+	activeMetrics.Store(metrics)
+	return metrics, nil
+}
+
+// DisableMetrics stops recording; registered collectors keep their last values.
+func DisableMetrics() {
+	activeMetrics.Store(nil)
+}
+
+func (m *Metrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.requests, m.latency, m.retries, m.limiterWait, m.cache, m.budget}
+}
+
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	for _, collector := range m.collectors() {
+		collector.Describe(ch)
+	}
+}
+
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	for _, collector := range m.collectors() {
+		collector.Collect(ch)
+	}
+}
+
+// SetRemainingBudget publishes how much of the named budget is left, e.g. requests left in the plan quota.
+func (m *Metrics) SetRemainingBudget(budget string, remaining float64) {
+	if m == nil {
+		return
+	}
+	m.budget.WithLabelValues(budget).Set(remaining)
+}
+
+func currentMetrics() *Metrics {
+	return activeMetrics.Load()
+}
+
+func (m *Metrics) observeAttempt(endpoint string, attempt int, status int, err error, latency time.Duration) {
+	if m == nil {
+		return
+	}
+	code := ""
+	var apiErr *APIError
+	switch {
+	case err == nil:
+		code = "0"
+	case errors.As(err, &apiErr):
+		code = strconv.Itoa(apiErr.Code)
+	}
+	m.requests.WithLabelValues(endpoint, strconv.Itoa(status), code).Inc()
+	m.latency.WithLabelValues(endpoint).Observe(latency.Seconds())
+	if attempt > 1 {
+		m.retries.WithLabelValues(endpoint).Inc()
+	}
+}
+
+func (m *Metrics) observeLimiterWait(wait time.Duration) {
+	if m == nil {
+		return
+	}
+	m.limiterWait.Observe(wait.Seconds())
+}
+
+func (m *Metrics) observeCache(endpoint string, result string) {
+	if m == nil {
+		return
+	}
+	m.cache.WithLabelValues(endpoint, result).Inc()
+}
@@ -0,0 +1,100 @@
+package oklink
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func setupMetrics(t *testing.T) *Metrics {
+	metrics, err := EnableMetrics(prometheus.NewRegistry())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	t.Cleanup(DisableMetrics)
+	return metrics
+}
+
+func TestMetricsRecordRequestsAndRetries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"code": 50011, "data": null, "msg": "Too many requests"}`))
+	}))
+	defer server.Close()
+	BASE_URL = server.URL + "/"
+	metrics := setupMetrics(t)
+	defer ResetMiddleware()
+	Use(Retry(2, time.Millisecond), RateLimit(1000, 1))
+
+	if _, err := AddressInfo("0xmetrics"); err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	endpoint := "address/address-summary"
+	if got := testutil.ToFloat64(metrics.requests.WithLabelValues(endpoint, "503", "")); got != 1 {
+		t.Errorf("Expected one 503 request, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.requests.WithLabelValues(endpoint, "200", "50011")); got != 1 {
+		t.Errorf("Expected one request with code 50011, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.retries.WithLabelValues(endpoint)); got != 1 {
+		t.Errorf("Expected one retry, got %v", got)
+	}
+	if got := testutil.CollectAndCount(metrics.latency); got != 1 {
+		t.Errorf("Expected one latency series, got %d", got)
+	}
+	var wait dto.Metric
+	if err := metrics.limiterWait.Write(&wait); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := wait.GetHistogram().GetSampleCount(); got != 2 {
+		t.Errorf("Expected a limiter observation per attempt, got %d", got)
+	}
+}
+
+func TestMetricsRecordCacheAndBudget(t *testing.T) {
+	server := setupMockServer(`{"code": 0, "data": [{"height": "100"}], "msg": ""}`, http.StatusOK)
+	defer server.Close()
+	BASE_URL = server.URL + "/"
+	metrics := setupMetrics(t)
+	SetCache(&Cache{Backend: NewMemoryCache(10), Policy: &FinalityPolicy{DefaultTTL: time.Minute}})
+	defer SetCache(nil)
+
+	for i := 0; i < 2; i++ {
+		if _, err := fetchApi[any](server.URL + "/api/v5/explorer/token/token-list"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if got := testutil.ToFloat64(metrics.cache.WithLabelValues("token/token-list", "hit")); got != 1 {
+		t.Errorf("Expected one cache hit, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.cache.WithLabelValues("token/token-list", "miss")); got != 1 {
+		t.Errorf("Expected one cache miss, got %v", got)
+	}
+
+	metrics.SetRemainingBudget("daily", 420)
+	if got := testutil.ToFloat64(metrics.budget.WithLabelValues("daily")); got != 420 {
+		t.Errorf("Expected remaining budget 420, got %v", got)
+	}
+}
+
+func TestEnableMetricsRejectsDuplicateRegistration(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	if _, err := EnableMetrics(registry); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer DisableMetrics()
+	if _, err := EnableMetrics(registry); err == nil {
+		t.Error("Expected duplicate registration to fail")
+	}
+}
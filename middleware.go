@@ -0,0 +1,233 @@
+package oklink
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Request describes one round-trip to the API. Middleware may edit HTTP, e.g. to add headers.
+type Request struct {
+	Endpoint string
+	Method   string
+	Params   url.Values
+	// Attempt starts at 1 and is incremented by Retry for each repeated round-trip.
+	Attempt int
+	HTTP    *http.Request
+
+	// keyBenched is set by KeyPool when it benched this attempt's key, so another key may succeed.
+	keyBenched bool
+}
+
+type Response struct {
+	StatusCode int
+	Latency    time.Duration
+	// Err is the decoded failure: an *APIError for a non-zero code, a *HTTPError for a bad status,
+	// or the transport or decoding error.
+	Err error
+}
+
+type Handler func(req *Request) *Response
+
+type Middleware func(next Handler) Handler
+
+type APIError struct {
+	Code int
+	Msg  string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error! code: %d, message: %s", e.Code, e.Msg)
+}
+
+type HTTPError struct {
+	StatusCode int
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("HTTP error! status: %d", e.StatusCode)
+}
+
+var (
+	middlewareMu sync.RWMutex
+	middlewares  []Middleware
+)
+
+// Use appends middleware to the chain every request goes through. The first registered runs outermost.
+// Responses served from the cache or shared by request coalescing do not pass through the chain.
+func Use(middleware ...Middleware) {
+	middlewareMu.Lock()
+	defer middlewareMu.Unlock()
+	middlewares = append(middlewares, middleware...)
+}
+
+// ResetMiddleware removes all registered middleware.
+func ResetMiddleware() {
+	middlewareMu.Lock()
+	defer middlewareMu.Unlock()
+	middlewares = nil
+}
+
+func chain(final Handler) Handler {
+	middlewareMu.RLock()
+	defer middlewareMu.RUnlock()
+	handler := final
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// RequestHook runs before each round-trip; returning an error aborts it.
+func RequestHook(hook func(req *Request) error) Middleware {
+	return func(next Handler) Handler {
+		return func(req *Request) *Response {
+			if err := hook(req); err != nil {
+				return &Response{Err: err}
+			}
+			return next(req)
+		}
+	}
+}
+
+// ResponseHook runs after each round-trip.
+func ResponseHook(hook func(req *Request, resp *Response)) Middleware {
+	return func(next Handler) Handler {
+		return func(req *Request) *Response {
+			resp := next(req)
+			hook(req, resp)
+			return resp
+		}
+	}
+}
+
+// Header sets a header on every request.
+func Header(name, value string) Middleware {
+	return RequestHook(func(req *Request) error {
+		req.HTTP.Header.Set(name, value)
+		return nil
+	})
+}
+
+// Retry repeats failed round-trips up to attempts times in total, doubling backoff after each one.
+// Only transport errors, 429 and 5xx responses and OKLink's rate-limit code are retried. With a KeyPool
+// inside Retry, any failure that benches the key, such as a revoked key, is retried with another key.
+func Retry(attempts int, backoff time.Duration) Middleware {
+	return func(next Handler) Handler {
+		return func(req *Request) *Response {
+			wait := backoff
+			for {
+				resp := next(req)
+				if req.Attempt >= attempts || !retryable(req, resp) {
+					return resp
+				}
+				select {
+				case <-req.HTTP.Context().Done():
+					return resp
+				case <-time.After(wait):
+				}
+				wait *= 2
+				req.Attempt++
+			}
+		}
+	}
+}
+
+// RateLimit holds round-trips, including retries, to perSecond with bursts of up to burst.
+func RateLimit(perSecond float64, burst int) Middleware {
+	limiter := newRateLimiter(perSecond, burst)
+	return func(next Handler) Handler {
+		return func(req *Request) *Response {
+			wait, err := limiter.Wait(req.HTTP.Context())
+			if err != nil {
+				return &Response{Err: err}
+			}
+			currentMetrics().observeLimiterWait(wait)
+			return next(req)
+		}
+	}
+}
+
+// rateLimitCodes are OKLink response codes worth retrying with the same key after a backoff.
+var rateLimitCodes = map[int]bool{
+	50011: true,
+}
+
+func retryable(req *Request, resp *Response) bool {
+	if resp.Err == nil {
+		return false
+	}
+	if req.keyBenched {
+		return true
+	}
+	var httpErr *HTTPError
+	if errors.As(resp.Err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= 500
+	}
+	var apiErr *APIError
+	if errors.As(resp.Err, &apiErr) {
+		return rateLimitCodes[apiErr.Code]
+	}
+	// Errors returned by middleware, such as an exhausted budget, are not transport failures.
+	var urlErr *url.Error
+	return errors.As(resp.Err, &urlErr)
+}
+
+// roundTrip sends req through the middleware chain, decoding into T and copying the raw body into buf
+// when buf is non-nil.
+func roundTrip[T any](req *http.Request, buf *bytes.Buffer) (*ApiResponse[T], error) {
+	endpoint := apiEndpoint(req.URL.Path)
+	var decoded *ApiResponse[T]
+	final := func(r *Request) *Response {
+		ctx, span := startSpan(r.HTTP.Context(), "oklink.attempt", trace.SpanKindClient,
+			attribute.String("oklink.endpoint", endpoint), attribute.Int("oklink.attempt", r.Attempt))
+		httpReq := r.HTTP
+		if ctx != httpReq.Context() {
+			httpReq = httpReq.WithContext(ctx)
+		}
+		if r.Attempt > 1 && httpReq.GetBody != nil {
+			body, err := httpReq.GetBody()
+			if err != nil {
+				err = fmt.Errorf("error creating request: %w", err)
+				endSpan(span, err)
+				return &Response{Err: err}
+			}
+			httpReq = httpReq.Clone(ctx)
+			httpReq.Body = body
+		}
+		return logAttempt(ctx, r, buf, func(buf *bytes.Buffer) *Response {
+			if buf != nil {
+				buf.Reset()
+			}
+			start := time.Now()
+			result, status, err := fetchInto[T](httpReq, buf)
+			latency := time.Since(start)
+			decoded = result
+			currentMetrics().observeAttempt(endpoint, r.Attempt, status, err, latency)
+			if status != 0 {
+				span.SetAttributes(statusAttribute(status))
+			}
+			endSpan(span, err)
+			return &Response{StatusCode: status, Latency: latency, Err: err}
+		})
+	}
+
+	resp := chain(final)(&Request{Endpoint: endpoint, Method: req.Method, Params: req.URL.Query(), Attempt: 1, HTTP: req})
+	if resp.StatusCode != 0 && tracingEnabled() {
+		trace.SpanFromContext(req.Context()).SetAttributes(statusAttribute(resp.StatusCode))
+	}
+	if resp.Err != nil {
+		return nil, resp.Err
+	}
+	if decoded == nil {
+		return nil, fmt.Errorf("no response for %s", endpoint)
+	}
+	return decoded, nil
+}
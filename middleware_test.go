@@ -0,0 +1,166 @@
+package oklink
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMiddlewareSeesRequestAndResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Ok-Access-Key") != "secret" {
+			t.Errorf("Expected injected header, got %q", r.Header.Get("Ok-Access-Key"))
+		}
+		w.Write([]byte(`{"code": 50011, "data": null, "msg": "Too many requests"}`))
+	}))
+	defer server.Close()
+	BASE_URL = server.URL + "/"
+	defer ResetMiddleware()
+
+	var seen *Request
+	var result *Response
+	Use(Header("Ok-Access-Key", "secret"), ResponseHook(func(req *Request, resp *Response) {
+		seen, result = req, resp
+	}))
+
+	_, err := AddressInfo("0xmiddleware")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 50011 {
+		t.Fatalf("Expected API error 50011, got %v", err)
+	}
+	if seen == nil {
+		t.Fatal("Expected the response hook to run")
+	}
+	if seen.Endpoint != "address/address-summary" || seen.Method != "GET" || seen.Attempt != 1 {
+		t.Errorf("Unexpected request %+v", seen)
+	}
+	if seen.Params.Get("address") != "0xmiddleware" {
+		t.Errorf("Expected address param, got %v", seen.Params)
+	}
+	if result.StatusCode != http.StatusOK || result.Latency <= 0 || result.Err != err {
+		t.Errorf("Unexpected response %+v", result)
+	}
+}
+
+func TestRequestHookAborts(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+	BASE_URL = server.URL + "/"
+	defer ResetMiddleware()
+
+	denied := errors.New("denied")
+	Use(RequestHook(func(req *Request) error { return denied }))
+
+	if _, err := AddressInfo("0xdenied"); !errors.Is(err, denied) {
+		t.Errorf("Expected hook error, got %v", err)
+	}
+	if calls.Load() != 0 {
+		t.Errorf("Expected no round-trip, got %d", calls.Load())
+	}
+}
+
+func TestRetryMiddleware(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"code": 0, "data": {"balance": "3"}, "msg": ""}`))
+	}))
+	defer server.Close()
+	BASE_URL = server.URL + "/"
+	defer ResetMiddleware()
+
+	var attempts []int
+	Use(Retry(3, time.Millisecond), ResponseHook(func(req *Request, resp *Response) {
+		attempts = append(attempts, req.Attempt)
+	}))
+
+	response, err := AddressInfo("0xretry")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Data.Balance != "3" {
+		t.Errorf("Expected balance 3, got %s", response.Data.Balance)
+	}
+	if len(attempts) != 3 || attempts[2] != 3 {
+		t.Errorf("Expected attempts 1..3, got %v", attempts)
+	}
+}
+
+func TestRetryStopsOnAPIError(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`{"code": 50014, "data": null, "msg": "Invalid parameter"}`))
+	}))
+	defer server.Close()
+	BASE_URL = server.URL + "/"
+	defer ResetMiddleware()
+
+	Use(Retry(3, time.Millisecond))
+	if _, err := AddressInfo("0xinvalid"); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected a single attempt, got %d", calls.Load())
+	}
+}
+
+func TestRetryRetriesRateLimitCode(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Write([]byte(`{"code": 50011, "data": null, "msg": "Too Many Requests"}`))
+			return
+		}
+		w.Write([]byte(`{"code": 0, "data": {"balance": "3"}, "msg": ""}`))
+	}))
+	defer server.Close()
+	BASE_URL = server.URL + "/"
+	defer ResetMiddleware()
+
+	Use(Retry(3, time.Millisecond))
+	if _, err := AddressInfo("0xlimited"); err != nil {
+		t.Fatalf("Expected the retry to succeed, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected two attempts, got %d", calls.Load())
+	}
+}
+
+func TestRetryLeavesAuthFailuresWithoutKeyPool(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"revoked key code", http.StatusOK, `{"code": 50111, "data": null, "msg": "Invalid OK-ACCESS-KEY"}`},
+		{"unauthorized status", http.StatusUnauthorized, ``},
+	} {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(c.status)
+			w.Write([]byte(c.body))
+		}))
+		BASE_URL = server.URL + "/"
+		Use(Retry(3, time.Millisecond))
+
+		if _, err := AddressInfo("0xauth"); err == nil {
+			t.Errorf("%s: expected an error", c.name)
+		}
+		if calls.Load() != 1 {
+			t.Errorf("%s: expected a single attempt without a key pool, got %d", c.name, calls.Load())
+		}
+		ResetMiddleware()
+		server.Close()
+	}
+}
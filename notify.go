@@ -0,0 +1,474 @@
+package oklink
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+const (
+	DefaultAlertTemplate   = `[{{.Rule}}] {{.Event.Direction}} {{.Event.Transaction.Amount}} {{symbol .}} on {{.Address}} (tx {{.Event.Transaction.TxId}}){{if .Reasons}}: {{join .Reasons ", "}}{{end}}`
+	DefaultSubjectTemplate = `OKLink alert {{.Rule}} for {{.Address}}`
+
+	SignatureHeader = "X-Oklink-Signature"
+	TimestampHeader = "X-Oklink-Timestamp"
+)
+
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+type NotifierOptions struct {
+	Template string
+	// SubjectTemplate is only used by SMTPNotifier.
+	SubjectTemplate string
+	MaxRetries      int
+	RetryDelay      time.Duration
+	RateLimit       float64
+	Burst           int
+}
+
+type WebhookNotifier struct {
+	URL     string
+	Secret  string
+	Client  *http.Client
+	Options NotifierOptions
+
+	template *template.Template
+	limiter  *rateLimiter
+}
+
+type SlackNotifier struct {
+	WebhookURL string
+	Client     *http.Client
+	Options    NotifierOptions
+
+	template *template.Template
+	limiter  *rateLimiter
+}
+
+type SMTPNotifier struct {
+	Addr    string
+	Auth    smtp.Auth
+	From    string
+	To      []string
+	Options NotifierOptions
+
+	template *template.Template
+	subject  *template.Template
+	limiter  *rateLimiter
+}
+
+type WebhookPayload struct {
+	Rule        string    `json:"rule"`
+	Address     string    `json:"address"`
+	Event       string    `json:"event"`
+	Direction   string    `json:"direction"`
+	TxId        string    `json:"txId"`
+	From        string    `json:"from"`
+	To          string    `json:"to"`
+	Amount      string    `json:"amount"`
+	Symbol      string    `json:"symbol"`
+	Height      int64     `json:"height"`
+	Reasons     []string  `json:"reasons"`
+	Labels      []string  `json:"labels,omitempty"`
+	Balance     *float64  `json:"balance,omitempty"`
+	Message     string    `json:"message"`
+	TriggeredAt time.Time `json:"triggeredAt"`
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+func NewWebhookNotifier(url string, secret string, options NotifierOptions) (*WebhookNotifier, error) {
+	tmpl, err := parseAlertTemplate("message", options.Template, DefaultAlertTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return &WebhookNotifier{
+		URL:      url,
+		Secret:   secret,
+		Client:   &http.Client{Timeout: 10 * time.Second},
+		Options:  options,
+		template: tmpl,
+		limiter:  newRateLimiter(options.RateLimit, options.Burst),
+	}, nil
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	message, err := renderAlert(n.template, alert)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(newWebhookPayload(alert, message))
+	if err != nil {
+		return fmt.Errorf("error encoding webhook payload: %w", err)
+	}
+	return deliver(ctx, n.Options, n.limiter, func() error {
+		header := http.Header{}
+		if n.Secret != "" {
+			timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+			header.Set(TimestampHeader, timestamp)
+			header.Set(SignatureHeader, SignWebhookPayload(n.Secret, timestamp, body))
+		}
+		return postJSON(ctx, n.Client, n.URL, header, body)
+	})
+}
+
+func NewSlackNotifier(webhookURL string, options NotifierOptions) (*SlackNotifier, error) {
+	tmpl, err := parseAlertTemplate("message", options.Template, DefaultAlertTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return &SlackNotifier{
+		WebhookURL: webhookURL,
+		Client:     &http.Client{Timeout: 10 * time.Second},
+		Options:    options,
+		template:   tmpl,
+		limiter:    newRateLimiter(options.RateLimit, options.Burst),
+	}, nil
+}
+
+func (n *SlackNotifier) Notify(ctx context.Context, alert Alert) error {
+	message, err := renderAlert(n.template, alert)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]string{"text": message})
+	if err != nil {
+		return fmt.Errorf("error encoding slack payload: %w", err)
+	}
+	return deliver(ctx, n.Options, n.limiter, func() error {
+		return postJSON(ctx, n.Client, n.WebhookURL, nil, body)
+	})
+}
+
+func NewSMTPNotifier(addr string, from string, to []string, options NotifierOptions) (*SMTPNotifier, error) {
+	if len(to) == 0 {
+		return nil, errors.New("smtp notifier needs at least one recipient")
+	}
+	tmpl, err := parseAlertTemplate("message", options.Template, DefaultAlertTemplate)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := parseAlertTemplate("subject", options.SubjectTemplate, DefaultSubjectTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return &SMTPNotifier{
+		Addr:     addr,
+		From:     from,
+		To:       to,
+		Options:  options,
+		template: tmpl,
+		subject:  subject,
+		limiter:  newRateLimiter(options.RateLimit, options.Burst),
+	}, nil
+}
+
+// headerLineBreaks turns CR and LF into spaces so rendered values cannot start new mail headers.
+var headerLineBreaks = strings.NewReplacer("\r", " ", "\n", " ")
+
+func (n *SMTPNotifier) Notify(ctx context.Context, alert Alert) error {
+	subject, err := renderAlert(n.subject, alert)
+	if err != nil {
+		return err
+	}
+	message, err := renderAlert(n.template, alert)
+	if err != nil {
+		return err
+	}
+
+	var mail bytes.Buffer
+	fmt.Fprintf(&mail, "From: %s\r\n", n.From)
+	fmt.Fprintf(&mail, "To: %s\r\n", strings.Join(n.To, ", "))
+	fmt.Fprintf(&mail, "Subject: %s\r\n", headerLineBreaks.Replace(subject))
+	fmt.Fprintf(&mail, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&mail, "MIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n")
+	mail.WriteString(strings.ReplaceAll(message, "\n", "\r\n"))
+	mail.WriteString("\r\n")
+
+	return deliver(ctx, n.Options, n.limiter, func() error {
+		return n.send(ctx, mail.Bytes())
+	})
+}
+
+// smtpTimeout bounds a delivery when ctx carries no deadline of its own.
+const smtpTimeout = 30 * time.Second
+
+// send does what smtp.SendMail does, but dials with ctx and drops the
+// connection once ctx ends so a hung server cannot block the caller.
+func (n *SMTPNotifier) send(ctx context.Context, msg []byte) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", n.Addr)
+	if err != nil {
+		return fmt.Errorf("error dialing smtp server: %w", err)
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		conn.SetDeadline(time.Now().Add(smtpTimeout))
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	err = n.converse(conn, msg)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
+func (n *SMTPNotifier) converse(conn net.Conn, msg []byte) error {
+	host, _, err := net.SplitHostPort(n.Addr)
+	if err != nil {
+		conn.Close()
+		return &permanentError{fmt.Errorf("error parsing smtp address: %w", err)}
+	}
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("error greeting smtp server: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return fmt.Errorf("error starting tls: %w", err)
+		}
+	}
+	if n.Auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return &permanentError{errors.New("smtp server does not support AUTH")}
+		}
+		if err := client.Auth(n.Auth); err != nil {
+			return fmt.Errorf("error authenticating: %w", err)
+		}
+	}
+	if err := client.Mail(n.From); err != nil {
+		return fmt.Errorf("error sending MAIL: %w", err)
+	}
+	for _, to := range n.To {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("error sending RCPT: %w", err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("error sending DATA: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("error writing message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("error finishing message: %w", err)
+	}
+	return client.Quit()
+}
+
+func NotifyAll(ctx context.Context, alerts []Alert, notifiers ...Notifier) error {
+	var errs []error
+	for _, alert := range alerts {
+		for _, notifier := range notifiers {
+			if err := notifier.Notify(ctx, alert); err != nil {
+				errs = append(errs, fmt.Errorf("error notifying %s alert: %w", alert.Rule, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func SignWebhookPayload(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func VerifyWebhookSignature(secret string, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(SignWebhookPayload(secret, timestamp, body)), []byte(signature))
+}
+
+func newWebhookPayload(alert Alert, message string) WebhookPayload {
+	tx := alert.Event.Transaction
+	return WebhookPayload{
+		Rule:        alert.Rule,
+		Address:     string(alert.Address),
+		Event:       string(alert.Event.Type),
+		Direction:   string(alert.Event.Direction),
+		TxId:        tx.TxId,
+		From:        tx.From,
+		To:          tx.To,
+		Amount:      tx.Amount,
+		Symbol:      alertSymbol(alert),
+		Height:      alert.Event.Height,
+		Reasons:     alert.Reasons,
+		Labels:      alert.Labels,
+		Balance:     alert.Balance,
+		Message:     message,
+		TriggeredAt: alert.TriggeredAt,
+	}
+}
+
+func parseAlertTemplate(name string, text string, fallback string) (*template.Template, error) {
+	if text == "" {
+		text = fallback
+	}
+	tmpl, err := template.New(name).Funcs(template.FuncMap{
+		"symbol": alertSymbol,
+		"join":   strings.Join,
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s template: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+func renderAlert(tmpl *template.Template, alert Alert) (string, error) {
+	var out strings.Builder
+	if err := tmpl.Execute(&out, alert); err != nil {
+		return "", fmt.Errorf("error rendering %s template: %w", tmpl.Name(), err)
+	}
+	return out.String(), nil
+}
+
+func alertSymbol(alert Alert) string {
+	if alert.Event.Transaction.TransactionSymbol != "" {
+		return alert.Event.Transaction.TransactionSymbol
+	}
+	return alert.Event.Transaction.Symbol
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, header http.Header, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return &permanentError{fmt.Errorf("error creating request: %w", err)}
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	response, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error making request: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("HTTP error! status: %d", response.StatusCode)
+	if response.StatusCode >= 400 && response.StatusCode < 500 && response.StatusCode != http.StatusTooManyRequests {
+		return &permanentError{err}
+	}
+	return err
+}
+
+func deliver(ctx context.Context, options NotifierOptions, limiter *rateLimiter, send func() error) error {
+	if _, err := limiter.Wait(ctx); err != nil {
+		return err
+	}
+	delay := options.RetryDelay
+	if delay <= 0 {
+		delay = 500 * time.Millisecond
+	}
+	var err error
+	for attempt := 0; attempt <= options.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+		if err = send(); err == nil {
+			return nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return err
+		}
+	}
+	return fmt.Errorf("giving up after %d attempts: %w", options.MaxRetries+1, err)
+}
+
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(perSecond float64, burst int) *rateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{rate: perSecond, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// delay reports how long until a token is free without taking one.
+func (l *rateLimiter) delay() time.Duration {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	tokens := min(l.burst, l.tokens+time.Since(l.last).Seconds()*l.rate)
+	if tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - tokens) / l.rate * float64(time.Second))
+}
+
+func (l *rateLimiter) Wait(ctx context.Context) (time.Duration, error) {
+	if l == nil {
+		return 0, nil
+	}
+	wait := l.reserve()
+	if wait <= 0 {
+		return 0, nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-timer.C:
+		return wait, nil
+	}
+}
@@ -0,0 +1,244 @@
+package oklink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testAlert() Alert {
+	return Alert{
+		Rule:    "large-usdt-in",
+		Address: "0xwallet",
+		Event: WatchEvent{
+			Type:        EventTransactionConfirmed,
+			Direction:   DirectionIncoming,
+			Transaction: AddressTransaction{TxId: "0x1", Amount: "25000", TransactionSymbol: "USDT"},
+		},
+		Reasons: []string{"amount 25000"},
+	}
+}
+
+func TestWebhookNotifierSignsAndRetries(t *testing.T) {
+	var attempts int
+	var payload WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if !VerifyWebhookSignature("secret", r.Header.Get(TimestampHeader), body, r.Header.Get(SignatureHeader)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.Unmarshal(body, &payload)
+	}))
+	defer server.Close()
+
+	notifier, err := NewWebhookNotifier(server.URL, "secret", NotifierOptions{MaxRetries: 2, RetryDelay: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := notifier.Notify(context.Background(), testAlert()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", attempts)
+	}
+	if payload.TxId != "0x1" || payload.Symbol != "USDT" {
+		t.Errorf("Expected payload for tx 0x1 in USDT, got %+v", payload)
+	}
+	if !strings.Contains(payload.Message, "incoming 25000 USDT on 0xwallet") {
+		t.Errorf("Expected rendered message, got %q", payload.Message)
+	}
+}
+
+func TestWebhookNotifierDoesNotRetryClientErrors(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	notifier, _ := NewWebhookNotifier(server.URL, "", NotifierOptions{MaxRetries: 3, RetryDelay: time.Millisecond})
+	if err := notifier.Notify(context.Background(), testAlert()); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestSlackNotifierTemplateAndRateLimit(t *testing.T) {
+	var texts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		texts = append(texts, body["text"])
+	}))
+	defer server.Close()
+
+	notifier, err := NewSlackNotifier(server.URL, NotifierOptions{Template: "{{.Rule}} {{symbol .}}", RateLimit: 20, Burst: 1})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := notifier.Notify(context.Background(), testAlert()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected rate limiting to space deliveries, took %s", elapsed)
+	}
+	if len(texts) != 3 || texts[0] != "large-usdt-in USDT" {
+		t.Errorf("Expected templated text, got %v", texts)
+	}
+}
+
+func setupMockSMTPServer(t *testing.T) (string, func() string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var mu sync.Mutex
+	var data strings.Builder
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		conn.Write([]byte("220 localhost ESMTP\r\n"))
+		inData := false
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					conn.Write([]byte("250 OK\r\n"))
+					continue
+				}
+				mu.Lock()
+				data.WriteString(line)
+				mu.Unlock()
+				continue
+			}
+			switch command := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				conn.Write([]byte("250 localhost\r\n"))
+			case command == "DATA":
+				inData = true
+				conn.Write([]byte("354 go ahead\r\n"))
+			case command == "QUIT":
+				conn.Write([]byte("221 bye\r\n"))
+				return
+			default:
+				conn.Write([]byte("250 OK\r\n"))
+			}
+		}
+	}()
+	t.Cleanup(func() { listener.Close() })
+	return listener.Addr().String(), func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		return data.String()
+	}
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	addr, received := setupMockSMTPServer(t)
+
+	notifier, err := NewSMTPNotifier(addr, "alerts@example.com", []string{"ops@example.com"}, NotifierOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := notifier.Notify(context.Background(), testAlert()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	mail := received()
+	if !strings.Contains(mail, "Subject: OKLink alert large-usdt-in for 0xwallet") {
+		t.Errorf("Expected subject header, got %q", mail)
+	}
+	if !strings.Contains(mail, "incoming 25000 USDT on 0xwallet") {
+		t.Errorf("Expected rendered body, got %q", mail)
+	}
+}
+
+func TestSMTPNotifierStripsHeaderLineBreaks(t *testing.T) {
+	addr, received := setupMockSMTPServer(t)
+
+	notifier, err := NewSMTPNotifier(addr, "alerts@example.com", []string{"ops@example.com"}, NotifierOptions{SubjectTemplate: "{{.Rule}}"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	alert := testAlert()
+	alert.Rule = "rule\rBcc: attacker@example.com"
+	if err := notifier.Notify(context.Background(), alert); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	mail := received()
+	if !strings.Contains(mail, "Subject: rule Bcc: attacker@example.com\r\n") {
+		t.Errorf("Expected line breaks in the subject to become spaces, got %q", mail)
+	}
+}
+
+func TestNewSMTPNotifierRejectsBadSubjectTemplate(t *testing.T) {
+	_, err := NewSMTPNotifier("localhost:25", "alerts@example.com", []string{"ops@example.com"}, NotifierOptions{SubjectTemplate: "{{.Rule"})
+	if err == nil {
+		t.Fatal("Expected error for bad subject template, got nil")
+	}
+}
+
+func TestSMTPNotifierHonoursContext(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		// Never greet, like a server that has hung.
+		io.Copy(io.Discard, conn)
+	}()
+
+	notifier, err := NewSMTPNotifier(listener.Addr().String(), "alerts@example.com", []string{"ops@example.com"}, NotifierOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = notifier.Notify(ctx, testAlert())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected Notify to return with ctx, took %s", elapsed)
+	}
+}
@@ -1,15 +1,23 @@
 package oklink
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// BASE_URL is where every request is sent; point it at a proxy or a test server to redirect the SDK.
+var BASE_URL = "https://www.oklink.com/"
+
 const (
-	BASE_URL string = "https://www.oklink.com/";
 	CHAIN_ID string = "8217";
 	CHAIN_FULLNAME string = "KLAYTN";
 	CHAIN_SHORTNAME string = "KLAYTN";
@@ -17,8 +25,6 @@ const (
 
 type Address string
 
-var address Address = fmt.Sprintf("%xstring", 0)
-
 type ProtocolType string
 
 const (
@@ -60,48 +66,261 @@ type AddressData struct {
 
 type ApiResponse[T any] struct {
 	Code 	int 	`json:"code"`
-	Data 	T 		`json:"code"`
-	Msg 	string 	`json:"code"`
+	Data 	T 		`json:"data"`
+	Msg 	string 	`json:"msg"`
 }
 
-func main()  {
-	info, err := AddressInfo("klaytn", "0x1234567890abcdef")
-	if err != nil {
-		fmt.Println("Error:", err)
-		return
-	}
-	fmt.Printf("Address Information: %+v\n", info)
+type PageInfo struct {
+	Page      string `json:"page"`
+	Limit     string `json:"limit"`
+	TotalPage string `json:"totalPage"`
+}
+
+type AddressTransactionPage struct {
+	PageInfo
+	ChainFullName    string               `json:"chainFullName"`
+	ChainShortName   string               `json:"chainShortName"`
+	TransactionLists []AddressTransaction `json:"transactionLists"`
+}
+
+type BatchTransactionPage struct {
+	PageInfo
+	TransactionList []AddressTransaction `json:"transactionList"`
+}
+
+type AddressTransaction struct {
+	TxId                 string `json:"txId"`
+	MethodId             string `json:"methodId"`
+	BlockHash            string `json:"blockHash"`
+	Height               string `json:"height"`
+	TransactionTime      string `json:"transactionTime"`
+	From                 string `json:"from"`
+	To                   string `json:"to"`
+	IsFromContract       bool   `json:"isFromContract"`
+	IsToContract         bool   `json:"isToContract"`
+	Amount               string `json:"amount"`
+	TransactionSymbol    string `json:"transactionSymbol"`
+	Symbol               string `json:"symbol"`
+	TxFee                string `json:"txFee"`
+	Nonce                string `json:"nonce"`
+	GasLimit             string `json:"gasLimit"`
+	GasUsed              string `json:"gasUsed"`
+	GasPrice             string `json:"gasPrice"`
+	State                string `json:"state"`
+	TokenId              string `json:"tokenId"`
+	TokenContractAddress string `json:"tokenContractAddress"`
+}
+
+type LargeTransactionPage struct {
+	PageInfo
+	ChainFullName   string             `json:"chainFullName"`
+	ChainShortName  string             `json:"chainShortName"`
+	TransactionList []LargeTransaction `json:"transactionList"`
+}
+
+type LargeTransaction struct {
+	TxId              string `json:"txid"`
+	BlockHash         string `json:"blockHash"`
+	Height            string `json:"height"`
+	TransactionTime   string `json:"transactionTime"`
+	From              string `json:"from"`
+	To                string `json:"to"`
+	IsFromContract    bool   `json:"isFromContract"`
+	IsToContract      bool   `json:"isToContract"`
+	Amount            string `json:"amount"`
+	TransactionSymbol string `json:"transactionSymbol"`
+	TxFee             string `json:"txfee"`
+	State             string `json:"state"`
+}
+
+type UnconfirmedTransactionPage struct {
+	PageInfo
+	ChainFullName   string                   `json:"chainFullName"`
+	ChainShortName  string                   `json:"chainShortName"`
+	TransactionList []UnconfirmedTransaction `json:"transactionList"`
+}
+
+type UnconfirmedTransaction struct {
+	TxId              string `json:"txid"`
+	MethodId          string `json:"methodId"`
+	Nonce             string `json:"nonce"`
+	GasPrice          string `json:"gasPrice"`
+	GasLimit          string `json:"gasLimit"`
+	Height            string `json:"height"`
+	TransactionTime   string `json:"transactionTime"`
+	From              string `json:"from"`
+	To                string `json:"to"`
+	IsFromContract    bool   `json:"isFromContract"`
+	IsToContract      bool   `json:"isToContract"`
+	Amount            string `json:"amount"`
+	TransactionSymbol string `json:"symbol"`
+	TxFee             string `json:"txfee"`
+	State             string `json:"state"`
+	TransactionType   string `json:"transactionType"`
+}
+
+type TransactionFills struct {
+	ChainFullName        string                `json:"chainFullName"`
+	ChainShortName       string                `json:"chainShortName"`
+	TxId                 string                `json:"txid"`
+	Height               string                `json:"height"`
+	TransactionTime      string                `json:"transactionTime"`
+	Amount               string                `json:"amount"`
+	TransactionSymbol    string                `json:"transactionSymbol"`
+	TxFee                string                `json:"txfee"`
+	Index                string                `json:"index"`
+	Confirm              string                `json:"confirm"`
+	InputDetails         []TransactionEndpoint `json:"inputDetails"`
+	OutputDetails        []TransactionEndpoint `json:"outputDetails"`
+	State                string                `json:"state"`
+	GasLimit             string                `json:"gasLimit"`
+	GasUsed              string                `json:"gasUsed"`
+	GasPrice             string                `json:"gasPrice"`
+	Nonce                string                `json:"nonce"`
+	TransactionType      string                `json:"transactionType"`
+	MethodId             string                `json:"methodId"`
+	ErrorLog             string                `json:"errorLog"`
+	InputData            string                `json:"inputData"`
+	TokenTransferDetails []TokenTransferDetail `json:"tokenTransferDetails"`
+}
+
+type TransactionEndpoint struct {
+	InputHash  string `json:"inputHash"`
+	OutputHash string `json:"outputHash"`
+	IsContract bool   `json:"isContract"`
+	Amount     string `json:"amount"`
+}
+
+type TokenTransferDetail struct {
+	Index                string `json:"index"`
+	Token                string `json:"token"`
+	TokenContractAddress string `json:"tokenContractAddress"`
+	Symbol               string `json:"symbol"`
+	From                 string `json:"from"`
+	To                   string `json:"to"`
+	TokenId              string `json:"tokenId"`
+	Amount               string `json:"amount"`
+}
+
+type EntityLabel struct {
+	Label   string `json:"label"`
+	Address string `json:"address"`
 }
 
 func fetchApi[T any](url string) (*ApiResponse[T], error) {
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", url, nil)
+	return fetchApiContext[T](context.Background(), url)
+}
+
+func fetchApiContext[T any](ctx context.Context, url string) (response *ApiResponse[T], err error) {
+	ctx, span := startCallSpan(ctx, url)
+	defer func() { endSpan(span, err) }()
+
+	cache := responseCache.Load()
+	if cache != nil {
+		if body, ok := cache.lookup(url); ok {
+			span.SetAttributes(attribute.Bool("oklink.cache_hit", true))
+			return decodeResponse[T](bytes.NewReader(body))
+		}
+	}
+	result, body, shared, err := inflight.do(ctx, url, cache != nil, func(buf *bytes.Buffer) (any, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error creating request: %w", err)
+		}
+		req.Header.Add("Content-Type", "application/json")
+		return roundTrip[T](req, buf)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
+		return nil, err
 	}
-	req.Header.Add("Content-Type", "application/json")
+	if shared {
+		span.SetAttributes(attribute.Bool("oklink.coalesced", true))
+		return decodeResponse[T](bytes.NewReader(body))
+	}
+	if cache != nil {
+		cache.store(url, body)
+	}
+	return result.(*ApiResponse[T]), nil
+}
 
-	response, err := client.Do(req)
+// FetchRaw sends a GET request through the same middleware, cache, coalescing and tracing as the
+// SDK's own endpoints and returns the undecoded body. It serves OKLink APIs that answer without the
+// code/msg/data envelope; check, when non-nil, rejects bodies carrying an error so they are never cached.
+func FetchRaw(req *http.Request, check func(body []byte) error) (body []byte, err error) {
+	url := req.URL.String()
+	ctx, span := startCallSpan(req.Context(), url)
+	defer func() { endSpan(span, err) }()
+
+	cache := responseCache.Load()
+	if cache != nil {
+		if body, ok := cache.lookup(url); ok {
+			span.SetAttributes(attribute.Bool("oklink.cache_hit", true))
+			return body, nil
+		}
+	}
+	_, body, shared, err := inflight.do(ctx, url, true, func(buf *bytes.Buffer) (any, error) {
+		if _, err := roundTrip[json.RawMessage](req.WithContext(ctx), buf); err != nil {
+			return nil, err
+		}
+		if check != nil {
+			if err := check(buf.Bytes()); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
 	if err != nil {
-		return nil,fmt.Errorf("error making request: %w", err);
+		return nil, err
+	}
+	if shared {
+		span.SetAttributes(attribute.Bool("oklink.coalesced", true))
+	} else if cache != nil {
+		cache.store(url, body)
 	}
-	defer response.Body.Close()
-	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP error! status: %d", response.StatusCode)
+	return body, nil
+}
+
+func postApi[T any](url string, payload any) (*ApiResponse[T], error) {
+	return postApiContext[T](context.Background(), url, payload)
+}
+
+func postApiContext[T any](ctx context.Context, url string, payload any) (response *ApiResponse[T], err error) {
+	ctx, span := startCallSpan(ctx, url)
+	defer func() { endSpan(span, err) }()
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding request body: %w", err)
 	}
-	body, err := io.ReadAll(response.Body)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
 	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %w", err)
+		return nil, fmt.Errorf("error creating request: %w", err)
 	}
+	req.Header.Add("Content-Type", "application/json")
+	return roundTrip[T](req, nil)
+}
+
+func decodeResponse[T any](body io.Reader) (*ApiResponse[T], error) {
 	var apiResponse ApiResponse[T]
-	if err := json.Unmarshal(body, &apiResponse); err != nil {
+	if err := json.NewDecoder(body).Decode(&apiResponse); err != nil {
 		return nil, fmt.Errorf("error unmarshalling response: %w", err)
 	}
 	if apiResponse.Code != 0 {
-		return nil, fmt.Errorf("API error! code: %d, message: %s", apiResponse.Code, apiResponse.Msg)
+		return nil, &APIError{Code: apiResponse.Code, Msg: apiResponse.Msg}
 	}
 	return &apiResponse, nil
+}
 
+func decodeData[T any](data any) (T, error) {
+	var out T
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return out, fmt.Errorf("error encoding response data: %w", err)
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return out, fmt.Errorf("error unmarshalling response data: %w", err)
+	}
+	return out, nil
 }
 
 	// response, err := http.Get(url);
@@ -120,19 +339,23 @@ func fetchApi[T any](url string) (*ApiResponse[T], error) {
 	// return &result, nil
 
 
-func AddressInfo(address Address) (*ApiResponse[AddressInformation], error) {
+func AddressInfo(address Address) (*ApiResponse[AddressData], error) {
+	return AddressInfoContext(context.Background(), address)
+}
+
+func AddressInfoContext(ctx context.Context, address Address) (*ApiResponse[AddressData], error) {
 	params := url.Values{}
 	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
-	params.Add("address", address)
+	params.Add("address", string(address))
 
 	url := fmt.Sprintf("%sapi/v5/explorer/address/address-summary?%s", BASE_URL, params.Encode())
-	return fetchApi[AddressInformation](url)
+	return fetchApiContext[AddressData](ctx, url)
 }
 
 func EvmAddressInfo(address Address) (*ApiResponse[any], error) {
 	params := url.Values{}
 	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
-	params.Add("address", address)
+	params.Add("address", string(address))
 
 	url := fmt.Sprintf("%sapi/v5/explorer/address/information-evm?%s", BASE_URL, params.Encode())
 	return fetchApi[any](url)
@@ -141,17 +364,21 @@ func EvmAddressInfo(address Address) (*ApiResponse[any], error) {
 func AddressActiveChain(address Address) (*ApiResponse[any], error) {
 	params := url.Values{}
 	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
-	params.Add("address", address)
+	params.Add("address", string(address))
 
 	url := fmt.Sprintf("%sapi/v5/explorer/address/address-active-chain?%s", BASE_URL, params.Encode())
 	return fetchApi[any](url)
 }
 
 func AddressTokenBalance(address Address, protocolType ProtocolType, tokenContractAddress *Address, page *string, limit *string) (*ApiResponse[any], error) {
+	return AddressTokenBalanceContext(context.Background(), address, protocolType, tokenContractAddress, page, limit)
+}
+
+func AddressTokenBalanceContext(ctx context.Context, address Address, protocolType ProtocolType, tokenContractAddress *Address, page *string, limit *string) (*ApiResponse[any], error) {
 	params := url.Values{}
 	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
-	params.Add("address", address)
-	params.Add("protocolType", protocolType)
+	params.Add("address", string(address))
+	params.Add("protocolType", string(protocolType))
 
 	if tokenContractAddress != nil {
 		params.Add("tokenContractAddress", string(*tokenContractAddress))
@@ -166,14 +393,14 @@ func AddressTokenBalance(address Address, protocolType ProtocolType, tokenContra
 	}
 
 	url := fmt.Sprintf("%sapi/v5/explorer/address/token-balance?%s", BASE_URL, params.Encode())
-	return fetchApi[any](url)
+	return fetchApiContext[any](ctx, url)
 }
 
 func AddressBalanceDetails(address Address, protocolType ProtocolType, tokenContractAddress *Address, page *string, limit *string) (*ApiResponse[any], error) {
 	params := url.Values{}
 	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
-	params.Add("address", address)
-	params.Add("protocolType", protocolType)
+	params.Add("address", string(address))
+	params.Add("protocolType", string(protocolType))
 
 	if tokenContractAddress != nil {
 		params.Add("tokenContractAddress", string(*tokenContractAddress))
@@ -192,15 +419,31 @@ func AddressBalanceDetails(address Address, protocolType ProtocolType, tokenCont
 }
 
 func AddressTokenTransactionList(address Address, protocolType ProtocolType, tokenContractAddress *Address, page *string, limit *string) (*ApiResponse[any], error) {
+	return AddressTokenTransactionListContext(context.Background(), address, protocolType, tokenContractAddress, nil, nil, nil, page, limit)
+}
+
+func AddressTokenTransactionListContext(ctx context.Context, address Address, protocolType ProtocolType, tokenContractAddress *Address, startBlockHeight *string, endBlockHeight *string, isFromOrTo *string, page *string, limit *string) (*ApiResponse[any], error) {
 	params := url.Values{}
 	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
-	params.Add("address", address)
-	params.Add("protocolType", protocolType)
+	params.Add("address", string(address))
+	params.Add("protocolType", string(protocolType))
 
 	if tokenContractAddress != nil {
 		params.Add("tokenContractAddress", string(*tokenContractAddress))
 	}
 
+	if startBlockHeight != nil {
+		params.Add("startBlockHeight", *startBlockHeight)
+	}
+
+	if endBlockHeight != nil {
+		params.Add("endBlockHeight", *endBlockHeight)
+	}
+
+	if isFromOrTo != nil {
+		params.Add("isFromOrTo", *isFromOrTo)
+	}
+
 	if page != nil {
 		params.Add("page", *page)
 	}
@@ -210,30 +453,34 @@ func AddressTokenTransactionList(address Address, protocolType ProtocolType, tok
 	}
 
 	url := fmt.Sprintf("%sapi/v5/explorer/address/token-transaction-list?%s", BASE_URL, params.Encode())
-	return fetchApi[any](url)
+	return fetchApiContext[any](ctx, url)
 }
 
 func AddressBalanceHistory(address Address, height string, tokenContractAddress *Address) (*ApiResponse[any], error) {
 	params := url.Values{}
 	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
-	params.Add("address", address)
+	params.Add("address", string(address))
 	params.Add("height", height)
 
 	if tokenContractAddress != nil {
 		params.Add("tokenContractAddress", string(*tokenContractAddress))
 	}
 
-	url := fmt.Sprintf("%sapi/v5/explorer/address/address-balance-fills?%s", BASE_URL, params.Encode())
+	url := fmt.Sprintf("%sapi/v5/explorer/block/address-balance-history?%s", BASE_URL, params.Encode())
 	return fetchApi[any](url)
 }
 
 func AddressTransactionList(address Address, protocolType *ProtocolType, symbol *string, startBlockHeight *string, endBlockHeight *string, isFromOrTo *string, page *string, limit *string) (*ApiResponse[any], error) {
+	return AddressTransactionListContext(context.Background(), address, protocolType, symbol, startBlockHeight, endBlockHeight, isFromOrTo, page, limit)
+}
+
+func AddressTransactionListContext(ctx context.Context, address Address, protocolType *ProtocolType, symbol *string, startBlockHeight *string, endBlockHeight *string, isFromOrTo *string, page *string, limit *string) (*ApiResponse[any], error) {
 	params := url.Values{}
 	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
-	params.Add("address", address)
+	params.Add("address", string(address))
 
 	if protocolType != nil {
-		params.Add("protocolType", *string(protocolType))
+		params.Add("protocolType", string(*protocolType))
 	}
 
 	if symbol != nil {
@@ -261,13 +508,17 @@ func AddressTransactionList(address Address, protocolType *ProtocolType, symbol
 	}
 
 	url := fmt.Sprintf("%sapi/v5/explorer/address/transaction-list?%s", BASE_URL, params.Encode())
-	return fetchApi[any](url)
+	return fetchApiContext[any](ctx, url)
 }
 
 func AddressNormalTransactionList(address Address, startBlockHeight *string, endBlockHeight *string, isFromOrTo *string, page *string, limit *string) (*ApiResponse[any], error)  {
+	return AddressNormalTransactionListContext(context.Background(), address, startBlockHeight, endBlockHeight, isFromOrTo, page, limit)
+}
+
+func AddressNormalTransactionListContext(ctx context.Context, address Address, startBlockHeight *string, endBlockHeight *string, isFromOrTo *string, page *string, limit *string) (*ApiResponse[any], error) {
 	params := url.Values{}
 	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
-	params.Add("address", address)
+	params.Add("address", string(address))
 
 	if startBlockHeight != nil {
 		params.Add("startBlockHeight", *startBlockHeight)
@@ -290,13 +541,13 @@ func AddressNormalTransactionList(address Address, startBlockHeight *string, end
 	}
 
 	url := fmt.Sprintf("%sapi/v5/explorer/address/normal-transaction-list?%s", BASE_URL, params.Encode())
-	return fetchApi[any](url)
+	return fetchApiContext[any](ctx, url)
 }
 
 func AddressInternalTransactionList(address Address, startBlockHeight *string, endBlockHeight *string, isFromOrTo *string, page *string, limit *string) (*ApiResponse[any], error)  {
 	params := url.Values{}
 	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
-	params.Add("address", address)
+	params.Add("address", string(address))
 
 	if startBlockHeight != nil {
 		params.Add("startBlockHeight", *startBlockHeight)
@@ -322,32 +573,10 @@ func AddressInternalTransactionList(address Address, startBlockHeight *string, e
 	return fetchApi[any](url)
 }
 
-func AddressTokenTransactionList(address Address, protocolType ProtocolType, tokenContractAddress *Address, page *string, limit *string) (*ApiResponse[any], error)  {
-	params := url.Values{}
-	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
-	params.Add("address", address)
-	params.Add("protocolType", protocolType)
-
-	if tokenContractAddress != nil {
-		params.Add("tokenContractAddress", *tokenContractAddress)
-	}
-
-	if page != nil {
-		params.Add("page", *page)
-	}
-
-	if limit != nil {
-		params.Add("limit", *limit)
-	}
-
-	url := fmt.Sprintf("%sapi/v5/explorer/address/token-transaction-list?%s", BASE_URL, params.Encode())
-	return fetchApi[any](url)
-}
-
 func AddressEntityLabels(address Address) (*ApiResponse[any], error) {
 	params := url.Values{}
 	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
-	params.Add("address", address)
+	params.Add("address", string(address))
 
 	url := fmt.Sprintf("%sapi/v5/explorer/address/entity-labels?%s", BASE_URL, params.Encode())
 	return fetchApi[any](url)
@@ -359,7 +588,7 @@ func RichList(address *Address) (*ApiResponse[any], error) {
 	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
 
 	if address != nil {
-		params.Add("address", *address)
+		params.Add("address", string(*address))
 	}
 
 	url := fmt.Sprintf("%sapi/v5/explorer/address/rich-list?%s", BASE_URL, params.Encode())
@@ -406,12 +635,16 @@ func TransactionList(blockhash *string, height *string, page *string, limit *str
 	return fetchApi[any](url)
 }
 
-func LargeTransactionList(type *string, height *string, page *string, limit *string) (*ApiResponse[any], error) {
+func LargeTransactionList(txType *string, height *string, page *string, limit *string) (*ApiResponse[any], error) {
+	return LargeTransactionListContext(context.Background(), txType, height, page, limit)
+}
+
+func LargeTransactionListContext(ctx context.Context, txType *string, height *string, page *string, limit *string) (*ApiResponse[any], error) {
 	params := url.Values{}
 	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
 
-	if type != nil {
-		params.Add("type", *type)
+	if txType != nil {
+		params.Add("type", *txType)
 	}
 
 	if height != nil {
@@ -426,11 +659,15 @@ func LargeTransactionList(type *string, height *string, page *string, limit *str
 		params.Add("limit", *limit)
 	}
 
-	url := fmt.Sprintf("%sapi/v5/explorer/transaction/transaction-list?%s", BASE_URL, params.Encode())
-	return fetchApi[any](url)
+	url := fmt.Sprintf("%sapi/v5/explorer/transaction/large-transaction-list?%s", BASE_URL, params.Encode())
+	return fetchApiContext[any](ctx, url)
 }
 
 func UnconfirmedTransactionList(page *string, limit *string) (*ApiResponse[any], error) {
+	return UnconfirmedTransactionListContext(context.Background(), page, limit)
+}
+
+func UnconfirmedTransactionListContext(ctx context.Context, page *string, limit *string) (*ApiResponse[any], error) {
 	params := url.Values{}
 	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
 
@@ -443,7 +680,7 @@ func UnconfirmedTransactionList(page *string, limit *string) (*ApiResponse[any],
 	}
 
 	url := fmt.Sprintf("%sapi/v5/explorer/transaction/unconfirmed-transaction-list?%s", BASE_URL, params.Encode())
-	return fetchApi[any](url)
+	return fetchApiContext[any](ctx, url)
 }
 
 func InternalTransactionDetails(txId string, page *string, limit *string) (*ApiResponse[any], error) {
@@ -468,8 +705,8 @@ func TokenTransactionDetails(txId string, protocolType ProtocolType, page *strin
 	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
 	params.Add("txId", txId)
 
-	if protocolType != nil {
-		params.Add("protocolType", protocolType)
+	if protocolType != "" {
+		params.Add("protocolType", string(protocolType))
 	}
 
 	if page != nil {
@@ -485,38 +722,42 @@ func TokenTransactionDetails(txId string, protocolType ProtocolType, page *strin
 }
 
 func TransactionDetails(txId string) (*ApiResponse[any], error) {
+	return TransactionDetailsContext(context.Background(), txId)
+}
+
+func TransactionDetailsContext(ctx context.Context, txId string) (*ApiResponse[any], error) {
 	params := url.Values{}
 	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
 	params.Add("txId", txId)
 
 	url := fmt.Sprintf("%sapi/v5/explorer/transaction/transaction-fills?%s", BASE_URL, params.Encode())
-	return fetchApi[any](url)
+	return fetchApiContext[any](ctx, url)
 }
 
 func TokenSupplyHistory(tokenContractAddress Address, height string) (*ApiResponse[any], error) {
 	params := url.Values{}
 	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
-	params.Add("tokenContractAddress", tokenContractAddress)
+	params.Add("tokenContractAddress", string(tokenContractAddress))
 	params.Add("height", height)
 
 	url := fmt.Sprintf("%sapi/v5/explorer/token/supply-history?%s", BASE_URL, params.Encode())
 	return fetchApi[any](url)
 }
 
-func TokenList(protocolType *ProtocolType, tokenContractAddress Address, startTime *string, endTime *string, orderBy *string, page *string, limit *string) (*ApiResponse[any], error) {
+func TokenList(protocolType *ProtocolType, tokenContractAddress *Address, startTime *string, endTime *string, orderBy *string, page *string, limit *string) (*ApiResponse[any], error) {
 	params := url.Values{}
 	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
 
 	if protocolType != nil {
-		params.Add("protocolType", protocolType)
+		params.Add("protocolType", string(*protocolType))
 	}
 
 	if tokenContractAddress != nil {
-		params.Add("tokenContractAddress", tokenContractAddress)
+		params.Add("tokenContractAddress", string(*tokenContractAddress))
 	}
 
 	if startTime != nil {
-		params.Add("startTime", startTime)
+		params.Add("startTime", *startTime)
 	}
 
 	if endTime != nil {
@@ -528,11 +769,11 @@ func TokenList(protocolType *ProtocolType, tokenContractAddress Address, startTi
 	}
 
 	if page != nil {
-		params.Add("page", page)
+		params.Add("page", *page)
 	}
 
 	if limit != nil {
-		params.Add("limit", limit)
+		params.Add("limit", *limit)
 	}
 
 	url := fmt.Sprintf("%sapi/v5/explorer/token/token-list?%s", BASE_URL, params.Encode())
@@ -540,12 +781,19 @@ func TokenList(protocolType *ProtocolType, tokenContractAddress Address, startTi
 }
 
 func TokenPositionList(tokenContractAddress *Address, holderAddress *Address, page *string, limit *string) (*ApiResponse[any], error) {
+	return TokenPositionListContext(context.Background(), tokenContractAddress, holderAddress, page, limit)
+}
+
+func TokenPositionListContext(ctx context.Context, tokenContractAddress *Address, holderAddress *Address, page *string, limit *string) (*ApiResponse[any], error) {
 	params := url.Values{}
 	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
-	params.Add("tokenContractAddress", tokenContractAddress)
-	
+
+	if tokenContractAddress != nil {
+		params.Add("tokenContractAddress", string(*tokenContractAddress))
+	}
+
 	if holderAddress != nil {
-		params.Add("holderAddress", *holderAddress)
+		params.Add("holderAddress", string(*holderAddress))
 	}
 
 	if page != nil {
@@ -553,20 +801,23 @@ func TokenPositionList(tokenContractAddress *Address, holderAddress *Address, pa
 	}
 
 	if limit != nil {
-		params.Add("limit", limit)
+		params.Add("limit", *limit)
 	}
 
 	url := fmt.Sprintf("%sapi/v5/explorer/token/position-list?%s", BASE_URL, params.Encode())
-	return fetchApi[any](url)
+	return fetchApiContext[any](ctx, url)
 }
 
 func TokenPositionStatistics(tokenContractAddress *Address, holderAddress *Address, page *string, limit *string) (*ApiResponse[any], error) {
 	params := url.Values{}
 	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
-	params.Add("tokenContractAddress", tokenContractAddress)
-	
+
+	if tokenContractAddress != nil {
+		params.Add("tokenContractAddress", string(*tokenContractAddress))
+	}
+
 	if holderAddress != nil {
-		params.Add("holderAddress", *holderAddress)
+		params.Add("holderAddress", string(*holderAddress))
 	}
 
 	if page != nil {
@@ -574,7 +825,7 @@ func TokenPositionStatistics(tokenContractAddress *Address, holderAddress *Addre
 	}
 
 	if limit != nil {
-		params.Add("limit", limit)
+		params.Add("limit", *limit)
 	}
 
 	url := fmt.Sprintf("%sapi/v5/explorer/token/position-statistics?%s", BASE_URL, params.Encode())
@@ -584,7 +835,7 @@ func TokenPositionStatistics(tokenContractAddress *Address, holderAddress *Addre
 func TokenTransferDetails(tokenContractAddress Address, maxAmount *string, minAmount *string, page *string, limit *string) (*ApiResponse[any], error) {
 	params := url.Values{}
 	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
-	params.Add("tokenContractAddress", tokenContractAddress)
+	params.Add("tokenContractAddress", string(tokenContractAddress))
 	
 	if maxAmount != nil {
 		params.Add("maxAmount", *maxAmount)
@@ -599,7 +850,7 @@ func TokenTransferDetails(tokenContractAddress Address, maxAmount *string, minAm
 	}
 
 	if limit != nil {
-		params.Add("limit", limit)
+		params.Add("limit", *limit)
 	}
 
 	url := fmt.Sprintf("%sapi/v5/explorer/token/transaction-list?%s", BASE_URL, params.Encode())
@@ -609,18 +860,18 @@ func TokenTransferDetails(tokenContractAddress Address, maxAmount *string, minAm
 func TokenTransactionStatistics(tokenContractAddress Address, orderBy *string, page *string, limit *string) (*ApiResponse[any], error) {
 	params := url.Values{}
 	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
-	params.Add("tokenContractAddress", tokenContractAddress)
+	params.Add("tokenContractAddress", string(tokenContractAddress))
 
 	if orderBy != nil {
 		params.Add("orderBy", *orderBy)
 	}
 
 	if page != nil {
-		params.Add("page", page)
+		params.Add("page", *page)
 	}
 
 	if limit != nil {
-		params.Add("limit", limit)
+		params.Add("limit", *limit)
 	}
 
 	url := fmt.Sprintf("%sapi/v5/explorer/token/token-list?%s", BASE_URL, params.Encode())
@@ -628,37 +879,41 @@ func TokenTransactionStatistics(tokenContractAddress Address, orderBy *string, p
 }
 
 func BatchAddressBalances(addresses []Address) (*ApiResponse[any], error) {
+	return BatchAddressBalancesContext(context.Background(), addresses)
+}
+
+func BatchAddressBalancesContext(ctx context.Context, addresses []Address) (*ApiResponse[any], error) {
 	if len(addresses) > 100 {
-		return "", errors.New("The maximum number of addresses is 100")
+		return nil, errors.New("The maximum number of addresses is 100")
 	}
 
 	params := url.Values{}
-	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)	
-	params.Add("addresses", strings.Join(addresses, ","))
+	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
+	params.Add("addresses", addressList(addresses))
 
 	url := fmt.Sprintf("%sapi/v5/explorer/address/balance-multi?%s", BASE_URL, params.Encode())
-	return fetchApi[any](url)
+	return fetchApiContext[any](ctx, url)
 }
 
 func BatchAddressTokenBalances(addresses []Address, protocolType *ProtocolType, page *string, limit *string) (*ApiResponse[any], error) {
 	if len(addresses) > 50 {
-		return "", errors.New("The maximum number of addresses is 50")
+		return nil, errors.New("The maximum number of addresses is 50")
 	}
 
 	params := url.Values{}
-	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)	
-	params.Add("addresses", strings.Join(addresses, ","))
+	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
+	params.Add("addresses", addressList(addresses))
 
 	if protocolType != nil {
-		params.Add("protocolType", protocolType)
+		params.Add("protocolType", string(*protocolType))
 	}
 
 	if page != nil {
-		params.Add("page", page)
+		params.Add("page", *page)
 	}
 
 	if limit != nil {
-		params.Add("limit", limit)
+		params.Add("limit", *limit)
 	}
 
 	url := fmt.Sprintf("%sapi/v5/explorer/address/token-balance-multi?%s", BASE_URL, params.Encode())
@@ -666,13 +921,17 @@ func BatchAddressTokenBalances(addresses []Address, protocolType *ProtocolType,
 }
 
 func BatchAddressNormalTransactionList(addresses []Address, startBlockHeight *string, endBlockHeight *string, isFromOrTo *string, page *string, limit *string) (*ApiResponse[any], error) {
+	return BatchAddressNormalTransactionListContext(context.Background(), addresses, startBlockHeight, endBlockHeight, isFromOrTo, page, limit)
+}
+
+func BatchAddressNormalTransactionListContext(ctx context.Context, addresses []Address, startBlockHeight *string, endBlockHeight *string, isFromOrTo *string, page *string, limit *string) (*ApiResponse[any], error) {
 	if len(addresses) > 50 {
-		return "", errors.New("The maximum number of addresses is 50")
+		return nil, errors.New("The maximum number of addresses is 50")
 	}
 
 	params := url.Values{}
-	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)	
-	params.Add("addresses", strings.Join(addresses, ","))
+	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
+	params.Add("addresses", addressList(addresses))
 
 	if startBlockHeight != nil {
 		params.Add("startBlockHeight", *startBlockHeight)
@@ -687,25 +946,25 @@ func BatchAddressNormalTransactionList(addresses []Address, startBlockHeight *st
 	}
 
 	if page != nil {
-		params.Add("page", page)
+		params.Add("page", *page)
 	}
 
 	if limit != nil {
-		params.Add("limit", limit)
+		params.Add("limit", *limit)
 	}
 
 	url := fmt.Sprintf("%sapi/v5/explorer/address/normal-transaction-list-multi?%s", BASE_URL, params.Encode())
-	return fetchApi[any](url)
+	return fetchApiContext[any](ctx, url)
 }
 
 func BatchAddressInternalTransactionList(addresses []Address, startBlockHeight *string, endBlockHeight *string, isFromOrTo *string, page *string, limit *string) (*ApiResponse[any], error) {
 	if len(addresses) > 20 {
-		return "", errors.New("The maximum number of addresses is 20")
+		return nil, errors.New("The maximum number of addresses is 20")
 	}
 
 	params := url.Values{}
-	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)	
-	params.Add("addresses", strings.Join(addresses, ","))
+	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
+	params.Add("addresses", addressList(addresses))
 
 	if startBlockHeight != nil {
 		params.Add("startBlockHeight", *startBlockHeight)
@@ -720,11 +979,11 @@ func BatchAddressInternalTransactionList(addresses []Address, startBlockHeight *
 	}
 
 	if page != nil {
-		params.Add("page", page)
+		params.Add("page", *page)
 	}
 
 	if limit != nil {
-		params.Add("limit", limit)
+		params.Add("limit", *limit)
 	}
 
 	url := fmt.Sprintf("%sapi/v5/explorer/address/internal-transaction-list-multi?%s", BASE_URL, params.Encode())
@@ -733,21 +992,21 @@ func BatchAddressInternalTransactionList(addresses []Address, startBlockHeight *
 
 func BatchAddressTokenTransactionList(addresses []Address, startBlockHeight string, endBlockHeight string, protocolType *ProtocolType, tokenContractAddress *Address, isFromOrTo *string, page *string, limit *string) (*ApiResponse[any], error) {
 	if len(addresses) > 20 {
-		return "", errors.New("The maximum number of addresses is 20")
+		return nil, errors.New("The maximum number of addresses is 20")
 	}
 
 	params := url.Values{}
-	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)	
-	params.Add("addresses", strings.Join(addresses, ","))
+	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
+	params.Add("addresses", addressList(addresses))
 	params.Add("startBlockHeight", startBlockHeight)
 	params.Add("endBlockHeight", endBlockHeight)
 
 	if tokenContractAddress != nil {
-		params.Add("tokenContractAddress", *tokenContractAddress)
+		params.Add("tokenContractAddress", string(*tokenContractAddress))
 	}
 
 	if protocolType != nil {
-		params.Add("protocolType", *protocolType)
+		params.Add("protocolType", string(*protocolType))
 	}
 
 	if isFromOrTo != nil {
@@ -755,11 +1014,11 @@ func BatchAddressTokenTransactionList(addresses []Address, startBlockHeight stri
 	}
 
 	if page != nil {
-		params.Add("page", page)
+		params.Add("page", *page)
 	}
 
 	if limit != nil {
-		params.Add("limit", limit)
+		params.Add("limit", *limit)
 	}
 
 	url := fmt.Sprintf("%sapi/v5/explorer/address/token-transaction-list-multi?%s", BASE_URL, params.Encode())
@@ -769,16 +1028,16 @@ func BatchAddressTokenTransactionList(addresses []Address, startBlockHeight stri
 func BatchTokenTransaction(tokenContractAddress Address, startBlockHeight string, endBlockHeight string, page *string, limit *string) (*ApiResponse[any], error) {
 	params := url.Values{}
 	params.Add("CHAIN_SHORTNAME", CHAIN_SHORTNAME)
-	params.Add("tokenContractAddress", tokenContractAddress)
+	params.Add("tokenContractAddress", string(tokenContractAddress))
 	params.Add("startBlockHeight", startBlockHeight)
 	params.Add("endBlockHeight", endBlockHeight)
 
 	if page != nil {
-		params.Add("page", page)
+		params.Add("page", *page)
 	}
 
 	if limit != nil {
-		params.Add("limit", limit)
+		params.Add("limit", *limit)
 	}
 
 	url := fmt.Sprintf("%sapi/v5/explorer/token/token-transaction-list-multi?%s", BASE_URL, params.Encode())
@@ -786,44 +1045,47 @@ func BatchTokenTransaction(tokenContractAddress Address, startBlockHeight string
 }
 
 func BatchTransactionDetails(txIds []string) (*ApiResponse[any], error) {
+	return BatchTransactionDetailsContext(context.Background(), txIds)
+}
+
+func BatchTransactionDetailsContext(ctx context.Context, txIds []string) (*ApiResponse[any], error) {
 	if len(txIds) > 20 {
-		return "", errors.New("the maximum number of transactions is 20")
+		return nil, errors.New("the maximum number of transactions is 20")
 	}
 
 	params := url.Values{}
-	params.Add("chainShortName", chainShortName)
+	params.Add("chainShortName", CHAIN_SHORTNAME)
 	params.Add("txIds", strings.Join(txIds, ","))
 
-	url := fmt.Sprintf("%sapi/v5/explorer/transaction/transaction-multi?%s", baseUrl, params.Encode())
-
-	return fetchApi(url)
+	url := fmt.Sprintf("%sapi/v5/explorer/transaction/transaction-multi?%s", BASE_URL, params.Encode())
+	return fetchApiContext[any](ctx, url)
 }
 
 func BatchInternalTransactionDetails(txIds []string) (*ApiResponse[any], error) {
 	if len(txIds) > 20 {
-		return "", errors.New("the maximum number of transactions is 20")
+		return nil, errors.New("the maximum number of transactions is 20")
 	}
 
 	params := url.Values{}
-	params.Add("chainShortName", chainShortName)
+	params.Add("chainShortName", CHAIN_SHORTNAME)
 	params.Add("txIds", strings.Join(txIds, ","))
 
-	url := fmt.Sprintf("%sapi/v5/explorer/transaction/internal-transaction-multi?%s", baseUrl, params.Encode())
+	url := fmt.Sprintf("%sapi/v5/explorer/transaction/internal-transaction-multi?%s", BASE_URL, params.Encode())
 
-	return fetchApi(url)
+	return fetchApi[any](url)
 }
 
 func BatchTokenTransactionDetails(txIds []string, protocolType *ProtocolType, page *string, limit *string) (*ApiResponse[any], error) {
 	if len(txIds) > 20 {
-		return "", errors.New("the maximum number of transactions is 20")
+		return nil, errors.New("the maximum number of transactions is 20")
 	}
 
 	params := url.Values{}
-	params.Add("chainShortName", chainShortName)
+	params.Add("chainShortName", CHAIN_SHORTNAME)
 	params.Add("txIds", strings.Join(txIds, ","))
 
 	if protocolType != nil {
-		params.Add("protocolType", *protocolType)
+		params.Add("protocolType", string(*protocolType))
 	}
 
 	if page != nil {
@@ -834,8 +1096,8 @@ func BatchTokenTransactionDetails(txIds []string, protocolType *ProtocolType, pa
 		params.Add("protocolType", *limit)
 	}
 
-	url := fmt.Sprintf("%sapi/v5/explorer/transaction/token-transfer-multi?%s", baseUrl, params.Encode())
+	url := fmt.Sprintf("%sapi/v5/explorer/transaction/token-transfer-multi?%s", BASE_URL, params.Encode())
 
-	return fetchApi(url)
+	return fetchApi[any](url)
 }
 //
\ No newline at end of file
@@ -3,8 +3,6 @@ package oklink
 import (
 	"net/http"
 	"net/http/httptest"
-	"net/url"
-	"strings"
 	"testing"
 )
 
@@ -22,7 +20,7 @@ func TestFetchApi(t *testing.T) {
 	defer server.Close()
 
 	apiUrl := server.URL
-	response, err := fetchApi[AddressInformation](apiUrl)
+	response, err := fetchApi[AddressData](apiUrl)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -41,7 +39,7 @@ func TestAddressInfo(t *testing.T) {
 	server := setupMockServer(mockResponse, http.StatusOK)
 	defer server.Close()
 
-	BASE_URL = server.URL // Override the base URL to point to the mock server
+	BASE_URL = server.URL + "/" // Override the base URL to point to the mock server
 
 	address := Address("0x1234567890abcdef")
 	response, err := AddressInfo(address)
@@ -63,7 +61,7 @@ func TestAddressTokenBalance(t *testing.T) {
 	server := setupMockServer(mockResponse, http.StatusOK)
 	defer server.Close()
 
-	BASE_URL = server.URL // Override the base URL to point to the mock server
+	BASE_URL = server.URL + "/" // Override the base URL to point to the mock server
 
 	address := Address("0x1234567890abcdef")
 	protocolType := Token20
@@ -86,7 +84,7 @@ func TestEvmAddressInfo(t *testing.T) {
 	server := setupMockServer(mockResponse, http.StatusOK)
 	defer server.Close()
 
-	BASE_URL = server.URL // Override the base URL to point to the mock server
+	BASE_URL = server.URL + "/" // Override the base URL to point to the mock server
 
 	address := Address("0x1234567890abcdef")
 	response, err := EvmAddressInfo(address)
@@ -108,7 +106,7 @@ func TestAddressActiveChain(t *testing.T) {
 	server := setupMockServer(mockResponse, http.StatusOK)
 	defer server.Close()
 
-	BASE_URL = server.URL // Override the base URL to point to the mock server
+	BASE_URL = server.URL + "/" // Override the base URL to point to the mock server
 
 	address := Address("0x1234567890abcdef")
 	response, err := AddressActiveChain(address)
@@ -130,7 +128,7 @@ func TestAddressBalanceDetails(t *testing.T) {
 	server := setupMockServer(mockResponse, http.StatusOK)
 	defer server.Close()
 
-	BASE_URL = server.URL // Override the base URL to point to the mock server
+	BASE_URL = server.URL + "/" // Override the base URL to point to the mock server
 
 	address := Address("0x1234567890abcdef")
 	protocolType := Token20
@@ -153,7 +151,7 @@ func TestAddressTransactionList(t *testing.T) {
 	server := setupMockServer(mockResponse, http.StatusOK)
 	defer server.Close()
 
-	BASE_URL = server.URL // Override the base URL to point to the mock server
+	BASE_URL = server.URL + "/" // Override the base URL to point to the mock server
 
 	address := Address("0x1234567890abcdef")
 	response, err := AddressTransactionList(address, nil, nil, nil, nil, nil, nil, nil)
@@ -175,7 +173,7 @@ func TestBatchAddressBalances(t *testing.T) {
 	server := setupMockServer(mockResponse, http.StatusOK)
 	defer server.Close()
 
-	BASE_URL = server.URL // Override the base URL to point to the mock server
+	BASE_URL = server.URL + "/" // Override the base URL to point to the mock server
 
 	addresses := []Address{"0x1234567890abcdef"}
 	response, err := BatchAddressBalances(addresses)
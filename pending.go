@@ -0,0 +1,400 @@
+package oklink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type TxStatus string
+
+const (
+	TxTracked   TxStatus = "tracked"
+	TxSeen      TxStatus = "seen"
+	TxIncluded  TxStatus = "included"
+	TxConfirmed TxStatus = "confirmed"
+	TxFailed    TxStatus = "failed"
+	TxReplaced  TxStatus = "replaced"
+	TxDropped   TxStatus = "dropped"
+)
+
+type TrackedTransaction struct {
+	TxId          string
+	From          Address
+	Nonce         string
+	Status        TxStatus
+	Height        int64
+	Confirmations int64
+	ReplacedBy    string
+	Details       *TransactionFills
+	SubmittedAt   time.Time
+	SeenAt        time.Time
+	IncludedAt    time.Time
+	FinishedAt    time.Time
+	lastSeen      time.Time
+}
+
+type TxTransition struct {
+	Transaction TrackedTransaction
+	From        TxStatus
+	To          TxStatus
+	At          time.Time
+}
+
+type PendingTracker struct {
+	Interval      time.Duration
+	Confirmations int64
+	DropAfter     time.Duration
+	MaxPages      int
+	OnTransition  func(TxTransition)
+	OnError       func(error)
+	LatestHeight  func() (int64, error)
+
+	mu     sync.Mutex
+	events chan TxTransition
+	txs    map[string]*TrackedTransaction
+}
+
+const pendingPageLimit = 100
+
+func NewPendingTracker(interval time.Duration) *PendingTracker {
+	return &PendingTracker{
+		Interval:      interval,
+		Confirmations: 1,
+		DropAfter:     10 * time.Minute,
+		MaxPages:      5,
+		events:        make(chan TxTransition, 64),
+		txs:           map[string]*TrackedTransaction{},
+	}
+}
+
+func (t *TrackedTransaction) Final() bool {
+	switch t.Status {
+	case TxConfirmed, TxFailed, TxReplaced, TxDropped:
+		return true
+	}
+	return false
+}
+
+func (t *TrackedTransaction) TimeToSeen() time.Duration {
+	return sinceSubmitted(t.SubmittedAt, t.SeenAt)
+}
+
+func (t *TrackedTransaction) TimeToInclusion() time.Duration {
+	return sinceSubmitted(t.SubmittedAt, t.IncludedAt)
+}
+
+func (t *TrackedTransaction) TimeToFinal() time.Duration {
+	return sinceSubmitted(t.SubmittedAt, t.FinishedAt)
+}
+
+func (p *PendingTracker) Track(txId string, from Address, nonce string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key := strings.ToLower(txId)
+	if _, ok := p.txs[key]; ok {
+		return
+	}
+	now := time.Now()
+	p.txs[key] = &TrackedTransaction{
+		TxId:        txId,
+		From:        from,
+		Nonce:       nonce,
+		Status:      TxTracked,
+		SubmittedAt: now,
+		lastSeen:    now,
+	}
+}
+
+func (p *PendingTracker) Forget(txId string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.txs, strings.ToLower(txId))
+}
+
+func (p *PendingTracker) Transactions() []TrackedTransaction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	txs := make([]TrackedTransaction, 0, len(p.txs))
+	for _, tx := range p.txs {
+		txs = append(txs, *tx)
+	}
+	return txs
+}
+
+func (p *PendingTracker) Events() <-chan TxTransition {
+	return p.events
+}
+
+func (p *PendingTracker) Run(ctx context.Context) error {
+	if p.Interval <= 0 {
+		return errors.New("pending tracker interval must be positive")
+	}
+	defer close(p.events)
+
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+	for {
+		if err := p.Poll(ctx); err != nil && p.OnError != nil {
+			p.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *PendingTracker) Poll(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	active := 0
+	for _, tx := range p.txs {
+		if !tx.Final() {
+			active++
+		}
+	}
+	if active == 0 {
+		return nil
+	}
+
+	pending, err := p.fetchPending(ctx)
+	if err != nil {
+		return err
+	}
+	byTxId := map[string]UnconfirmedTransaction{}
+	byNonce := map[string]UnconfirmedTransaction{}
+	for _, tx := range pending {
+		byTxId[strings.ToLower(tx.TxId)] = tx
+		byNonce[nonceKey(Address(tx.From), tx.Nonce)] = tx
+	}
+
+	// Transitions are worked out on a copy and only applied to the tracked transaction once emitted, so a
+	// failed lookup or emit leaves the transaction to be reconsidered by the next poll.
+	var latest int64
+	var updates []pendingUpdate
+	var errs []error
+	now := time.Now()
+	for key, tracked := range p.txs {
+		if tracked.Final() {
+			continue
+		}
+		tx := *tracked
+		transitions, err := p.check(ctx, key, &tx, byTxId, byNonce, &latest, now)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(transitions) == 0 {
+			*tracked = tx
+			continue
+		}
+		updates = append(updates, pendingUpdate{tx: tracked, transitions: transitions})
+	}
+
+	for _, update := range updates {
+		for _, transition := range update.transitions {
+			if err := p.emit(ctx, transition); err != nil {
+				return errors.Join(append(errs, err)...)
+			}
+			*update.tx = transition.Transaction
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type pendingUpdate struct {
+	tx          *TrackedTransaction
+	transitions []TxTransition
+}
+
+// check works out the transitions of one tracked transaction, updating tx in place.
+func (p *PendingTracker) check(ctx context.Context, key string, tx *TrackedTransaction, byTxId map[string]UnconfirmedTransaction, byNonce map[string]UnconfirmedTransaction, latest *int64, now time.Time) ([]TxTransition, error) {
+	if unconfirmed, ok := byTxId[key]; ok {
+		if tx.From == "" {
+			tx.From = Address(unconfirmed.From)
+		}
+		if tx.Nonce == "" {
+			tx.Nonce = unconfirmed.Nonce
+		}
+		tx.lastSeen = now
+		if tx.Status == TxTracked {
+			tx.SeenAt = now
+			return []TxTransition{p.transition(tx, TxSeen, now)}, nil
+		}
+		return nil, nil
+	}
+
+	if replacement, ok := byNonce[nonceKey(tx.From, tx.Nonce)]; ok && tx.Nonce != "" && tx.Status != TxIncluded {
+		tx.ReplacedBy = replacement.TxId
+		return []TxTransition{p.transition(tx, TxReplaced, now)}, nil
+	}
+
+	details, err := transactionFills(ctx, tx.TxId)
+	if err != nil {
+		return nil, err
+	}
+	if details != nil && details.Height != "" && details.Height != "0" {
+		if *latest == 0 && p.Confirmations > 1 {
+			if *latest, err = p.latestHeight(); err != nil {
+				return nil, fmt.Errorf("error fetching latest height for transaction %s: %w", tx.TxId, err)
+			}
+		}
+		return p.mined(tx, details, *latest, now), nil
+	}
+
+	if tx.From != "" && tx.Nonce != "" && tx.Status != TxIncluded {
+		replacedBy, err := minedReplacement(ctx, tx)
+		if err != nil {
+			return nil, err
+		}
+		if replacedBy != "" {
+			tx.ReplacedBy = replacedBy
+			return []TxTransition{p.transition(tx, TxReplaced, now)}, nil
+		}
+	}
+
+	if p.DropAfter > 0 && now.Sub(tx.lastSeen) > p.DropAfter {
+		return []TxTransition{p.transition(tx, TxDropped, now)}, nil
+	}
+	return nil, nil
+}
+
+func (p *PendingTracker) mined(tx *TrackedTransaction, details *TransactionFills, latest int64, now time.Time) []TxTransition {
+	var transitions []TxTransition
+	tx.Details = details
+	tx.Height, _ = strconv.ParseInt(details.Height, 10, 64)
+	if tx.Nonce == "" {
+		tx.Nonce = details.Nonce
+	}
+	tx.Confirmations = confirmations(latest, tx.Height)
+	if confirm, err := strconv.ParseInt(details.Confirm, 10, 64); err == nil && confirm > tx.Confirmations {
+		tx.Confirmations = confirm
+	}
+
+	if tx.Status == TxTracked || tx.Status == TxSeen {
+		if tx.SeenAt.IsZero() {
+			tx.SeenAt = now
+		}
+		tx.IncludedAt = now
+		transitions = append(transitions, p.transition(tx, TxIncluded, now))
+	}
+	if strings.EqualFold(details.State, "fail") {
+		transitions = append(transitions, p.transition(tx, TxFailed, now))
+	} else if tx.Confirmations >= p.Confirmations {
+		transitions = append(transitions, p.transition(tx, TxConfirmed, now))
+	}
+	return transitions
+}
+
+func (p *PendingTracker) transition(tx *TrackedTransaction, status TxStatus, now time.Time) TxTransition {
+	from := tx.Status
+	tx.Status = status
+	if tx.Final() {
+		tx.FinishedAt = now
+	}
+	return TxTransition{Transaction: *tx, From: from, To: status, At: now}
+}
+
+func (p *PendingTracker) latestHeight() (int64, error) {
+	if p.LatestHeight != nil {
+		return p.LatestHeight()
+	}
+	return latestBlockHeight()
+}
+
+func (p *PendingTracker) fetchPending(ctx context.Context) (txs []UnconfirmedTransaction, err error) {
+	ctx, span := startPageSpan(ctx, "transaction/unconfirmed-transaction-list")
+	defer func() { endSpan(span, err) }()
+
+	limit := strconv.Itoa(pendingPageLimit)
+	maxPages := p.MaxPages
+	if maxPages <= 0 {
+		maxPages = 1
+	}
+	for page := 1; page <= maxPages; page++ {
+		pageNumber := strconv.Itoa(page)
+		response, err := UnconfirmedTransactionListContext(ctx, &pageNumber, &limit)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching unconfirmed transactions: %w", err)
+		}
+		pages, err := decodeData[[]UnconfirmedTransactionPage](response.Data)
+		if err != nil {
+			return nil, err
+		}
+		if len(pages) == 0 {
+			break
+		}
+		txs = append(txs, pages[0].TransactionList...)
+		if !pages[0].hasNext() {
+			break
+		}
+	}
+	return txs, nil
+}
+
+func (p *PendingTracker) emit(ctx context.Context, transition TxTransition) error {
+	if p.OnTransition != nil {
+		p.OnTransition(transition)
+		return nil
+	}
+	select {
+	case p.events <- transition:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func transactionFills(ctx context.Context, txId string) (*TransactionFills, error) {
+	response, err := TransactionDetailsContext(ctx, txId)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching transaction %s: %w", txId, err)
+	}
+	details, err := decodeData[[]TransactionFills](response.Data)
+	if err != nil {
+		return nil, err
+	}
+	if len(details) == 0 {
+		return nil, nil
+	}
+	return &details[0], nil
+}
+
+func minedReplacement(ctx context.Context, tx *TrackedTransaction) (string, error) {
+	isFromOrTo := "from"
+	limit := "50"
+	response, err := AddressNormalTransactionListContext(ctx, tx.From, nil, nil, &isFromOrTo, nil, &limit)
+	if err != nil {
+		return "", fmt.Errorf("error fetching transactions of %s: %w", tx.From, err)
+	}
+	pages, err := decodeData[[]BatchTransactionPage](response.Data)
+	if err != nil {
+		return "", err
+	}
+	for _, page := range pages {
+		for _, mined := range page.TransactionList {
+			if mined.Nonce == tx.Nonce && !strings.EqualFold(mined.TxId, tx.TxId) {
+				return mined.TxId, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func nonceKey(from Address, nonce string) string {
+	return strings.ToLower(string(from)) + "|" + nonce
+}
+
+func sinceSubmitted(submitted time.Time, at time.Time) time.Duration {
+	if at.IsZero() {
+		return 0
+	}
+	return at.Sub(submitted)
+}
@@ -0,0 +1,163 @@
+package oklink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPendingTrackerLifecycle(t *testing.T) {
+	mined := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/blockchain/summary"):
+			w.Write([]byte(`{"code": 0, "data": [{"lastHeight": "102"}], "msg": ""}`))
+		case strings.HasSuffix(r.URL.Path, "/unconfirmed-transaction-list"):
+			if mined {
+				w.Write([]byte(`{"code": 0, "data": [{"page": "1", "totalPage": "1", "transactionList": []}], "msg": ""}`))
+				return
+			}
+			w.Write([]byte(`{"code": 0, "data": [{"page": "1", "totalPage": "1", "transactionList": [
+				{"txid": "0xa", "from": "0xsender", "nonce": "5"},
+				{"txid": "0xb2", "from": "0xother", "nonce": "1"}
+			]}], "msg": ""}`))
+		case strings.HasSuffix(r.URL.Path, "/transaction-fills"):
+			if mined && r.URL.Query().Get("txId") == "0xa" {
+				w.Write([]byte(`{"code": 0, "data": [{"txid": "0xa", "height": "100", "state": "success"}], "msg": ""}`))
+				return
+			}
+			w.Write([]byte(`{"code": 0, "data": [], "msg": ""}`))
+		case strings.HasSuffix(r.URL.Path, "/normal-transaction-list"):
+			w.Write([]byte(`{"code": 0, "data": [{"page": "1", "totalPage": "1", "transactionList": []}], "msg": ""}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	var transitions []string
+	tracker := NewPendingTracker(0)
+	tracker.Confirmations = 3
+	tracker.DropAfter = time.Hour
+	tracker.OnTransition = func(transition TxTransition) {
+		transitions = append(transitions, fmt.Sprintf("%s:%s", transition.Transaction.TxId, transition.To))
+	}
+	tracker.Track("0xa", "", "")
+	tracker.Track("0xb", "0xother", "1")
+
+	if err := tracker.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	mined = true
+	if err := tracker.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	got := strings.Join(transitions, ",")
+	for _, want := range []string{"0xa:seen", "0xb:replaced", "0xa:included", "0xa:confirmed"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected transition %s, got %s", want, got)
+		}
+	}
+
+	for _, tx := range tracker.Transactions() {
+		if tx.TxId == "0xa" && (tx.Confirmations != 3 || tx.TimeToFinal() <= 0) {
+			t.Errorf("Expected 3 confirmations with timing, got %d after %s", tx.Confirmations, tx.TimeToFinal())
+		}
+		if tx.TxId == "0xb" && tx.ReplacedBy != "0xb2" {
+			t.Errorf("Expected 0xb replaced by 0xb2, got %s", tx.ReplacedBy)
+		}
+	}
+}
+
+func TestPendingTrackerDropsMissingTransaction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/unconfirmed-transaction-list") {
+			w.Write([]byte(`{"code": 0, "data": [{"page": "1", "totalPage": "1", "transactionList": []}], "msg": ""}`))
+			return
+		}
+		w.Write([]byte(`{"code": 0, "data": [], "msg": ""}`))
+	}))
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	var last TxTransition
+	tracker := NewPendingTracker(0)
+	tracker.DropAfter = time.Millisecond
+	tracker.OnTransition = func(transition TxTransition) { last = transition }
+	tracker.Track("0xgone", "", "")
+
+	time.Sleep(5 * time.Millisecond)
+	if err := tracker.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if last.To != TxDropped || last.From != TxTracked {
+		t.Errorf("Expected tracked to dropped, got %s to %s", last.From, last.To)
+	}
+}
+
+func TestPendingTrackerSkipsFailedLookups(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/unconfirmed-transaction-list"):
+			w.Write([]byte(`{"code": 0, "data": [{"page": "1", "totalPage": "1", "transactionList": [{"txid": "0xc", "from": "0xsender", "nonce": "7"}]}], "msg": ""}`))
+		case strings.HasSuffix(r.URL.Path, "/transaction-fills"):
+			switch r.URL.Query().Get("txId") {
+			case "0xa":
+				w.Write([]byte(`{"code": 0, "data": [{"txid": "0xa", "height": "100", "state": "success"}], "msg": ""}`))
+			case "0xbad":
+				w.WriteHeader(http.StatusInternalServerError)
+			default:
+				w.Write([]byte(`{"code": 0, "data": [], "msg": ""}`))
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	var transitions []string
+	tracker := NewPendingTracker(0)
+	tracker.Confirmations = 3
+	tracker.DropAfter = time.Hour
+	headAvailable := false
+	tracker.LatestHeight = func() (int64, error) {
+		if !headAvailable {
+			return 0, errors.New("summary unavailable")
+		}
+		return 102, nil
+	}
+	tracker.OnTransition = func(transition TxTransition) {
+		transitions = append(transitions, fmt.Sprintf("%s:%s>%s", transition.Transaction.TxId, transition.From, transition.To))
+	}
+	tracker.Track("0xa", "", "")
+	tracker.Track("0xbad", "", "")
+	tracker.Track("0xc", "", "")
+
+	err := tracker.Poll(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "summary unavailable") || !strings.Contains(err.Error(), "0xbad") {
+		t.Fatalf("Expected errors for 0xa and 0xbad, got %v", err)
+	}
+	if got := strings.Join(transitions, ","); got != "0xc:tracked>seen" {
+		t.Fatalf("Expected only 0xc to move, got %s", got)
+	}
+
+	headAvailable = true
+	transitions = nil
+	if err := tracker.Poll(context.Background()); err == nil {
+		t.Fatal("Expected 0xbad to keep failing, got nil")
+	}
+	if got := strings.Join(transitions, ","); got != "0xa:tracked>included,0xa:included>confirmed" {
+		t.Errorf("Expected 0xa to be included then confirmed, got %s", got)
+	}
+}
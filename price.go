@@ -0,0 +1,246 @@
+package oklink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type TokenPrice struct {
+	ChainId              string `json:"chainId"`
+	TokenContractAddress string `json:"tokenContractAddress"`
+	LastPrice            string `json:"lastPrice"`
+}
+
+type TokenHistoricalPrice struct {
+	Time  string `json:"time"`
+	Price string `json:"price"`
+}
+
+type TokenMarketData struct {
+	ChainId                string `json:"chainId"`
+	TokenContractAddress   string `json:"tokenContractAddress"`
+	LastPrice              string `json:"lastPrice"`
+	TotalSupply            string `json:"totalSupply"`
+	CirculatingSupply      string `json:"circulatingSupply"`
+	MarketCap              string `json:"marketCap"`
+	CirculatingMarketCap   string `json:"circulatingMarketCap"`
+	Volume24h              string `json:"volume24h"`
+	PriceAbsoluteChange24h string `json:"priceAbsoluteChange24h"`
+	PriceChange24h         string `json:"priceChange24h"`
+	HolderAmount           string `json:"holderAmount"`
+}
+
+const maxPriceTokens = 100
+
+// priceHistorySize bounds how many historical prices a PriceCache keeps before evicting the least recently used.
+const priceHistorySize = 10000
+
+var pricePeriods = []struct {
+	period   string
+	duration time.Duration
+}{
+	{"1d", 24 * time.Hour},
+	{"4h", 4 * time.Hour},
+	{"1h", time.Hour},
+	{"30m", 30 * time.Minute},
+	{"5m", 5 * time.Minute},
+	{"1m", time.Minute},
+}
+
+func TokenPrices(tokenContractAddresses []Address) (*ApiResponse[[]TokenPrice], error) {
+	return TokenPricesContext(context.Background(), tokenContractAddresses)
+}
+
+func TokenPricesContext(ctx context.Context, tokenContractAddresses []Address) (*ApiResponse[[]TokenPrice], error) {
+	if len(tokenContractAddresses) > maxPriceTokens {
+		return nil, errors.New("the maximum number of tokens is 100")
+	}
+	params := url.Values{}
+	params.Add("chainId", CHAIN_ID)
+	params.Add("tokenContractAddress", addressList(tokenContractAddresses))
+
+	url := fmt.Sprintf("%sapi/v5/explorer/tokenprice/price-multi?%s", BASE_URL, params.Encode())
+	return fetchApiContext[[]TokenPrice](ctx, url)
+}
+
+func TokenHistoricalPrices(tokenContractAddress Address, period *string, after *string, before *string, limit *string) (*ApiResponse[[]TokenHistoricalPrice], error) {
+	params := url.Values{}
+	params.Add("chainId", CHAIN_ID)
+	params.Add("tokenContractAddress", string(tokenContractAddress))
+
+	if period != nil {
+		params.Add("period", *period)
+	}
+
+	if after != nil {
+		params.Add("after", *after)
+	}
+
+	if before != nil {
+		params.Add("before", *before)
+	}
+
+	if limit != nil {
+		params.Add("limit", *limit)
+	}
+
+	url := fmt.Sprintf("%sapi/v5/explorer/tokenprice/historical?%s", BASE_URL, params.Encode())
+	return fetchApi[[]TokenHistoricalPrice](url)
+}
+
+func TokenMarketDetails(tokenContractAddress Address) (*ApiResponse[[]TokenMarketData], error) {
+	params := url.Values{}
+	params.Add("chainId", CHAIN_ID)
+	params.Add("tokenContractAddress", string(tokenContractAddress))
+
+	url := fmt.Sprintf("%sapi/v5/explorer/tokenprice/market-data?%s", BASE_URL, params.Encode())
+	return fetchApi[[]TokenMarketData](url)
+}
+
+type PriceCache struct {
+	// Bucket is the resolution of historical lookups; it is rounded down to a period the API supports.
+	Bucket time.Duration
+	// CurrentTTL bounds how long a current price is reused.
+	CurrentTTL time.Duration
+	Now        func() time.Time
+
+	mu      sync.Mutex
+	history *MemoryCache
+	current map[string]cachedPrice
+}
+
+type cachedPrice struct {
+	price     float64
+	fetchedAt time.Time
+}
+
+func NewPriceCache(bucket time.Duration) *PriceCache {
+	return &PriceCache{
+		Bucket:     bucket,
+		CurrentTTL: time.Minute,
+		history:    NewMemoryCache(priceHistorySize),
+		current:    map[string]cachedPrice{},
+	}
+}
+
+func (c *PriceCache) Price(tokenContractAddress Address) (float64, error) {
+	prices, err := c.Prices([]Address{tokenContractAddress})
+	if err != nil {
+		return 0, err
+	}
+	price, ok := prices[strings.ToLower(string(tokenContractAddress))]
+	if !ok {
+		return 0, fmt.Errorf("no price for token %s", tokenContractAddress)
+	}
+	return price, nil
+}
+
+// Prices returns current prices keyed by lowercased contract address, fetching only the stale ones.
+func (c *PriceCache) Prices(tokenContractAddresses []Address) (map[string]float64, error) {
+	return c.PricesContext(context.Background(), tokenContractAddresses)
+}
+
+func (c *PriceCache) PricesContext(ctx context.Context, tokenContractAddresses []Address) (map[string]float64, error) {
+	now := c.now()
+	prices := map[string]float64{}
+	var missing []Address
+	c.mu.Lock()
+	for _, address := range tokenContractAddresses {
+		key := strings.ToLower(string(address))
+		if cached, ok := c.current[key]; ok && now.Sub(cached.fetchedAt) < c.CurrentTTL {
+			prices[key] = cached.price
+			continue
+		}
+		missing = append(missing, address)
+	}
+	c.mu.Unlock()
+
+	for start := 0; start < len(missing); start += maxPriceTokens {
+		end := start + maxPriceTokens
+		if end > len(missing) {
+			end = len(missing)
+		}
+		batchCtx, span := startBatchSpan(ctx, "tokenprice/price-multi", start/maxPriceTokens, end-start)
+		response, err := TokenPricesContext(batchCtx, missing[start:end])
+		endSpan(span, err)
+		if err != nil {
+			return prices, fmt.Errorf("error fetching token prices: %w", err)
+		}
+		c.mu.Lock()
+		for _, entry := range response.Data {
+			price, err := strconv.ParseFloat(entry.LastPrice, 64)
+			if err != nil {
+				continue
+			}
+			key := strings.ToLower(entry.TokenContractAddress)
+			prices[key] = price
+			c.current[key] = cachedPrice{price: price, fetchedAt: now}
+		}
+		c.mu.Unlock()
+	}
+	return prices, nil
+}
+
+func (c *PriceCache) PriceAt(tokenContractAddress Address, at time.Time) (float64, error) {
+	period, bucket := c.period()
+	start := at.Truncate(bucket)
+	key := fmt.Sprintf("%s@%d", strings.ToLower(string(tokenContractAddress)), start.UnixMilli())
+
+	if cached, ok, _ := c.history.Get(key); ok {
+		if price, err := strconv.ParseFloat(string(cached), 64); err == nil {
+			return price, nil
+		}
+	}
+
+	// after returns records older than the timestamp, so this picks the candle the bucket falls in.
+	after := strconv.FormatInt(start.Add(bucket).UnixMilli(), 10)
+	limit := "1"
+	response, err := TokenHistoricalPrices(tokenContractAddress, &period, &after, nil, &limit)
+	if err != nil {
+		return 0, fmt.Errorf("error fetching price history for %s: %w", tokenContractAddress, err)
+	}
+	if len(response.Data) == 0 {
+		return 0, fmt.Errorf("no price for token %s at %s", tokenContractAddress, at.UTC().Format(time.RFC3339))
+	}
+	price, err := strconv.ParseFloat(response.Data[0].Price, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing price for %s: %w", tokenContractAddress, err)
+	}
+
+	// Closed buckets never change; the current one is still forming and is left uncached.
+	if !start.Add(bucket).After(c.now()) {
+		c.history.Set(key, []byte(response.Data[0].Price), 0)
+	}
+	return price, nil
+}
+
+func (c *PriceCache) period() (string, time.Duration) {
+	for _, p := range pricePeriods {
+		if c.Bucket >= p.duration {
+			return p.period, p.duration
+		}
+	}
+	last := pricePeriods[len(pricePeriods)-1]
+	return last.period, last.duration
+}
+
+func (c *PriceCache) now() time.Time {
+	if c.Now != nil {
+		return c.Now()
+	}
+	return time.Now()
+}
+
+func addressList(addresses []Address) string {
+	list := make([]string, len(addresses))
+	for i, address := range addresses {
+		list[i] = string(address)
+	}
+	return strings.Join(list, ",")
+}
@@ -0,0 +1,91 @@
+package oklink
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenPrices(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`{"code": 0, "data": [{"chainId": "8217", "tokenContractAddress": "0xA", "lastPrice": "1.5"}, {"chainId": "8217", "tokenContractAddress": "0xb", "lastPrice": "0.25"}], "msg": ""}`))
+	}))
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	cache := NewPriceCache(time.Hour)
+	prices, err := cache.Prices([]Address{"0xA", "0xb"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(query, "chainId="+CHAIN_ID) || !strings.Contains(query, "tokenContractAddress=0xA%2C0xb") {
+		t.Errorf("Unexpected query %s", query)
+	}
+	if prices["0xa"] != 1.5 || prices["0xb"] != 0.25 {
+		t.Errorf("Expected prices keyed by lowercased address, got %v", prices)
+	}
+
+	query = ""
+	if price, err := cache.Price("0xa"); err != nil || price != 1.5 || query != "" {
+		t.Errorf("Expected cached price 1.5, got %v, %v after query %q", price, err, query)
+	}
+}
+
+func TestPriceCachePriceAt(t *testing.T) {
+	requests := 0
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		query = r.URL.RawQuery
+		w.Write([]byte(`{"code": 0, "data": [{"time": "1700000000000", "price": "2.75"}], "msg": ""}`))
+	}))
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	cache := NewPriceCache(90 * time.Minute)
+	at := time.UnixMilli(1700000000000).Add(10 * time.Minute)
+	for _, ts := range []time.Time{at, at.Add(5 * time.Minute)} {
+		price, err := cache.PriceAt("0xToken", ts)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if price != 2.75 {
+			t.Errorf("Expected price 2.75, got %v", price)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Expected one request for the same bucket, got %d", requests)
+	}
+	if !strings.Contains(query, "period=1h") || !strings.Contains(query, "limit=1") {
+		t.Errorf("Unexpected query %s", query)
+	}
+}
+
+func TestPriceCacheEvictsOldHistory(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"code": 0, "data": [{"time": "1700000000000", "price": "2.75"}], "msg": ""}`))
+	}))
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	cache := NewPriceCache(time.Hour)
+	cache.history = NewMemoryCache(1)
+	at := time.UnixMilli(1700000000000)
+	for _, ts := range []time.Time{at, at.Add(time.Hour), at} {
+		if _, err := cache.PriceAt("0xToken", ts); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if requests != 3 {
+		t.Errorf("Expected the evicted bucket to be fetched again, got %d requests", requests)
+	}
+}
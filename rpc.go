@@ -0,0 +1,241 @@
+package oklink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	rpcMethodNotFound = -32601
+	nativeDecimals    = 18
+)
+
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("RPC error! code: %d, message: %s", e.Code, e.Message)
+}
+
+type RPCClient struct {
+	URL string
+	// Namespace is tried first; methods a node does not know are retried under eth_.
+	Namespace string
+
+	id      atomic.Int64
+	ethOnly atomic.Bool
+}
+
+type rpcRequest struct {
+	JsonRpc string `json:"jsonrpc"`
+	Id      int64  `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type rpcResponse struct {
+	Id     int64           `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+type rpcTransaction struct {
+	Hash        string `json:"hash"`
+	BlockHash   string `json:"blockHash"`
+	BlockNumber string `json:"blockNumber"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Gas         string `json:"gas"`
+	GasPrice    string `json:"gasPrice"`
+	Input       string `json:"input"`
+	Nonce       string `json:"nonce"`
+	Value       string `json:"value"`
+}
+
+type rpcReceipt struct {
+	Status            string `json:"status"`
+	GasUsed           string `json:"gasUsed"`
+	EffectiveGasPrice string `json:"effectiveGasPrice"`
+	ContractAddress   string `json:"contractAddress"`
+	TxError           string `json:"txError"`
+}
+
+type rpcBlock struct {
+	Number        string            `json:"number"`
+	Hash          string            `json:"hash"`
+	Timestamp     string            `json:"timestamp"`
+	Miner         string            `json:"miner"`
+	Proposer      string            `json:"proposer"`
+	Size          string            `json:"size"`
+	GasUsed       string            `json:"gasUsed"`
+	GasLimit      string            `json:"gasLimit"`
+	BaseFeePerGas string            `json:"baseFeePerGas"`
+	Transactions  []json.RawMessage `json:"transactions"`
+}
+
+func NewRPCClient(url string) *RPCClient {
+	return &RPCClient{URL: url, Namespace: "kaia"}
+}
+
+// Call invokes method (without namespace prefix) and unmarshals the result into result.
+func (c *RPCClient) Call(ctx context.Context, method string, result any, params ...any) error {
+	namespace := c.Namespace
+	if namespace == "" || c.ethOnly.Load() {
+		namespace = "eth"
+	}
+	err := c.call(ctx, namespace+"_"+method, result, params)
+	var rpcErr *RPCError
+	if namespace != "eth" && errors.As(err, &rpcErr) && rpcErr.Code == rpcMethodNotFound {
+		c.ethOnly.Store(true)
+		return c.call(ctx, "eth_"+method, result, params)
+	}
+	return err
+}
+
+func (c *RPCClient) call(ctx context.Context, method string, result any, params []any) error {
+	if params == nil {
+		params = []any{}
+	}
+	body, err := json.Marshal(rpcRequest{JsonRpc: "2.0", Id: c.id.Add(1), Method: method, Params: params})
+	if err != nil {
+		return fmt.Errorf("error encoding request body: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	response, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error making request: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP error! status: %d", response.StatusCode)
+	}
+	raw, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response body: %w", err)
+	}
+	var rpcResp rpcResponse
+	if err := json.Unmarshal(raw, &rpcResp); err != nil {
+		return fmt.Errorf("error unmarshalling response: %w", err)
+	}
+	if rpcResp.Error != nil {
+		return rpcResp.Error
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(rpcResp.Result, result); err != nil {
+		return fmt.Errorf("error unmarshalling %s result: %w", method, err)
+	}
+	return nil
+}
+
+func (c *RPCClient) BlockNumber(ctx context.Context) (int64, error) {
+	var height string
+	if err := c.Call(ctx, "blockNumber", &height); err != nil {
+		return 0, err
+	}
+	value, err := parseQuantity(height)
+	if err != nil {
+		return 0, err
+	}
+	return value.Int64(), nil
+}
+
+func (c *RPCClient) Balance(ctx context.Context, address Address) (*big.Int, error) {
+	var balance string
+	if err := c.Call(ctx, "getBalance", &balance, string(address), "latest"); err != nil {
+		return nil, err
+	}
+	return parseQuantity(balance)
+}
+
+func (c *RPCClient) transaction(ctx context.Context, txId string) (*rpcTransaction, error) {
+	var tx *rpcTransaction
+	if err := c.Call(ctx, "getTransactionByHash", &tx, txId); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (c *RPCClient) receipt(ctx context.Context, txId string) (*rpcReceipt, error) {
+	var receipt *rpcReceipt
+	if err := c.Call(ctx, "getTransactionReceipt", &receipt, txId); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+func (c *RPCClient) block(ctx context.Context, number string) (*rpcBlock, error) {
+	var block *rpcBlock
+	if err := c.Call(ctx, "getBlockByNumber", &block, number, false); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+func parseQuantity(value string) (*big.Int, error) {
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(value, "0x"), "0X")
+	if trimmed == "" {
+		return new(big.Int), nil
+	}
+	quantity, ok := new(big.Int).SetString(trimmed, 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid hex quantity %q", value)
+	}
+	return quantity, nil
+}
+
+func quantityString(value string) string {
+	quantity, err := parseQuantity(value)
+	if err != nil {
+		return ""
+	}
+	return quantity.String()
+}
+
+func toQuantity(height string) (string, error) {
+	if height == "" || height == "latest" {
+		return "latest", nil
+	}
+	value, ok := new(big.Int).SetString(height, 10)
+	if !ok {
+		return "", fmt.Errorf("invalid block height %q", height)
+	}
+	return "0x" + value.Text(16), nil
+}
+
+// formatUnits renders an integer amount of the smallest unit as a decimal string.
+func formatUnits(value *big.Int, decimals int) string {
+	if value == nil {
+		return "0"
+	}
+	negative := value.Sign() < 0
+	digits := new(big.Int).Abs(value).String()
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	whole, fraction := digits[:len(digits)-decimals], strings.TrimRight(digits[len(digits)-decimals:], "0")
+	result := whole
+	if fraction != "" {
+		result += "." + fraction
+	}
+	if negative {
+		result = "-" + result
+	}
+	return result
+}
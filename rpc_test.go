@@ -0,0 +1,93 @@
+package oklink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func setupRPCServer(results map[string]any) (*httptest.Server, *[]string) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		methods = append(methods, req.Method)
+		response := map[string]any{"jsonrpc": "2.0", "id": req.Id}
+		if result, ok := results[req.Method]; ok {
+			response["result"] = result
+		} else {
+			response["error"] = RPCError{Code: rpcMethodNotFound, Message: "method not found"}
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	return server, &methods
+}
+
+func TestRPCClientBalance(t *testing.T) {
+	server, methods := setupRPCServer(map[string]any{"kaia_getBalance": "0xde0b6b3a7640000"})
+	defer server.Close()
+
+	balance, err := NewRPCClient(server.URL).Balance(context.Background(), "0xabc")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if balance.Cmp(big.NewInt(1e18)) != 0 {
+		t.Errorf("Expected 1e18, got %s", balance)
+	}
+	if len(*methods) != 1 || (*methods)[0] != "kaia_getBalance" {
+		t.Errorf("Expected kaia namespace, got %v", *methods)
+	}
+}
+
+func TestRPCClientFallsBackToEthNamespace(t *testing.T) {
+	server, methods := setupRPCServer(map[string]any{"eth_blockNumber": "0x64"})
+	defer server.Close()
+
+	client := NewRPCClient(server.URL)
+	for i := 0; i < 2; i++ {
+		height, err := client.BlockNumber(context.Background())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if height != 100 {
+			t.Errorf("Expected height 100, got %d", height)
+		}
+	}
+	if len(*methods) != 3 {
+		t.Errorf("Expected the kaia namespace to be tried once, got %v", *methods)
+	}
+}
+
+func TestFormatUnits(t *testing.T) {
+	cases := map[string]string{
+		"0":                    "0",
+		"1":                    "0.000000000000000001",
+		"1500000000000000000":  "1.5",
+		"25000000000":          "0.000000025",
+		"-2000000000000000000": "-2",
+	}
+	for input, expected := range cases {
+		value, _ := new(big.Int).SetString(input, 10)
+		if got := formatUnits(value, 18); got != expected {
+			t.Errorf("formatUnits(%s) = %s, expected %s", input, got, expected)
+		}
+	}
+}
+
+func TestRPCClientHonoursContext(t *testing.T) {
+	server, methods := setupRPCServer(map[string]any{"kaia_blockNumber": "0x64"})
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewRPCClient(server.URL).BlockNumber(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if len(*methods) != 0 {
+		t.Errorf("Expected no request to reach the node, got %v", *methods)
+	}
+}
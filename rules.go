@@ -0,0 +1,345 @@
+package oklink
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Rule struct {
+	Name              string         `yaml:"name"`
+	Addresses         []Address      `yaml:"addresses"`
+	Event             WatchEventType `yaml:"event"`
+	Direction         Direction      `yaml:"direction"`
+	ProtocolType      ProtocolType   `yaml:"protocolType"`
+	Symbol            string         `yaml:"symbol"`
+	MinAmount         *float64       `yaml:"minAmount"`
+	MaxAmount         *float64       `yaml:"maxAmount"`
+	CounterpartyLabel string         `yaml:"counterpartyLabel"`
+	BalanceBelow      *float64       `yaml:"balanceBelow"`
+	Silence           time.Duration  `yaml:"silence"`
+}
+
+type RuleSet struct {
+	DedupWindow time.Duration `yaml:"dedupWindow"`
+	Rules       []Rule        `yaml:"rules"`
+}
+
+type Alert struct {
+	Rule        string
+	Address     Address
+	Event       WatchEvent
+	Reasons     []string
+	Labels      []string
+	Balance     *float64
+	TriggeredAt time.Time
+}
+
+type RuleTestResult struct {
+	Rule    string
+	Event   WatchEvent
+	Matched bool
+	Reason  string
+}
+
+type RuleEngine struct {
+	RuleSet RuleSet
+	Labels  func(Address) ([]string, error)
+	Balance func(Address) (float64, error)
+	Now     func() time.Time
+
+	mu       sync.Mutex
+	silenced map[string]time.Time
+	fired    map[string]time.Time
+}
+
+const defaultDedupWindow = 24 * time.Hour
+
+func LoadRules(r io.Reader) (RuleSet, error) {
+	var ruleSet RuleSet
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&ruleSet); err != nil {
+		return RuleSet{}, fmt.Errorf("error decoding rules: %w", err)
+	}
+	return ruleSet, ruleSet.Validate()
+}
+
+func LoadRulesFile(path string) (RuleSet, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return RuleSet{}, fmt.Errorf("error opening rules file: %w", err)
+	}
+	defer file.Close()
+	return LoadRules(file)
+}
+
+func (s RuleSet) Validate() error {
+	names := map[string]bool{}
+	for i, rule := range s.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("rule %d has no name", i)
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("duplicate rule name %q", rule.Name)
+		}
+		names[rule.Name] = true
+		switch rule.Event {
+		case "", EventTransactionSeen, EventTransactionConfirmed:
+		default:
+			return fmt.Errorf("rule %q has unknown event %q", rule.Name, rule.Event)
+		}
+		switch rule.Direction {
+		case "", DirectionIncoming, DirectionOutgoing, DirectionSelf:
+		default:
+			return fmt.Errorf("rule %q has unknown direction %q", rule.Name, rule.Direction)
+		}
+		if rule.MinAmount != nil && rule.MaxAmount != nil && *rule.MinAmount > *rule.MaxAmount {
+			return fmt.Errorf("rule %q has minAmount above maxAmount", rule.Name)
+		}
+		if rule.Silence < 0 {
+			return fmt.Errorf("rule %q has a negative silence window", rule.Name)
+		}
+	}
+	return nil
+}
+
+func NewRuleEngine(ruleSet RuleSet) (*RuleEngine, error) {
+	if err := ruleSet.Validate(); err != nil {
+		return nil, err
+	}
+	return &RuleEngine{
+		RuleSet:  ruleSet,
+		Labels:   entityLabels,
+		Balance:  nativeBalance,
+		Now:      time.Now,
+		silenced: map[string]time.Time{},
+		fired:    map[string]time.Time{},
+	}, nil
+}
+
+// Evaluate returns the alerts event triggers. Rules whose lookups fail are skipped and their errors
+// joined, so alerts from the other rules, which are already deduplicated, are still returned.
+func (e *RuleEngine) Evaluate(event WatchEvent) ([]Alert, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.Now()
+	e.prune(now)
+
+	var alerts []Alert
+	var errs []error
+	for _, rule := range e.RuleSet.Rules {
+		alert, reason, err := e.match(rule, event)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error evaluating rule %q: %w", rule.Name, err))
+			continue
+		}
+		if reason != "" {
+			continue
+		}
+
+		dedupKey := rule.Name + "|" + watchKey(event.Address, event.Transaction)
+		if _, ok := e.fired[dedupKey]; ok {
+			continue
+		}
+		silenceKey := rule.Name + "|" + strings.ToLower(string(event.Address))
+		if until, ok := e.silenced[silenceKey]; ok && now.Before(until) {
+			continue
+		}
+
+		e.fired[dedupKey] = now
+		if rule.Silence > 0 {
+			e.silenced[silenceKey] = now.Add(rule.Silence)
+		}
+		alert.TriggeredAt = now
+		alerts = append(alerts, alert)
+	}
+	return alerts, errors.Join(errs...)
+}
+
+func (e *RuleEngine) Test(events []WatchEvent) ([]RuleTestResult, error) {
+	var results []RuleTestResult
+	for _, event := range events {
+		for _, rule := range e.RuleSet.Rules {
+			_, reason, err := e.match(rule, event)
+			if err != nil {
+				return results, fmt.Errorf("error evaluating rule %q: %w", rule.Name, err)
+			}
+			results = append(results, RuleTestResult{
+				Rule:    rule.Name,
+				Event:   event,
+				Matched: reason == "",
+				Reason:  reason,
+			})
+		}
+	}
+	return results, nil
+}
+
+func (e *RuleEngine) match(rule Rule, event WatchEvent) (Alert, string, error) {
+	alert := Alert{Rule: rule.Name, Address: event.Address, Event: event}
+	tx := event.Transaction
+
+	wantEvent := rule.Event
+	if wantEvent == "" {
+		wantEvent = EventTransactionConfirmed
+	}
+	if event.Type != wantEvent {
+		return alert, fmt.Sprintf("event is %s, rule wants %s", event.Type, wantEvent), nil
+	}
+
+	if len(rule.Addresses) > 0 && !containsAddress(rule.Addresses, event.Address) {
+		return alert, fmt.Sprintf("address %s is not covered by the rule", event.Address), nil
+	}
+
+	if rule.Direction != "" {
+		if event.Direction != rule.Direction {
+			return alert, fmt.Sprintf("direction is %s, rule wants %s", event.Direction, rule.Direction), nil
+		}
+		alert.Reasons = append(alert.Reasons, fmt.Sprintf("direction %s", event.Direction))
+	}
+
+	if rule.ProtocolType != "" {
+		if !matchesProtocol(tx, rule.ProtocolType) {
+			return alert, fmt.Sprintf("transaction is not a %s transfer", rule.ProtocolType), nil
+		}
+		alert.Reasons = append(alert.Reasons, fmt.Sprintf("protocol %s", rule.ProtocolType))
+	}
+
+	if rule.Symbol != "" {
+		symbol := tx.TransactionSymbol
+		if symbol == "" {
+			symbol = tx.Symbol
+		}
+		if !strings.EqualFold(symbol, rule.Symbol) {
+			return alert, fmt.Sprintf("symbol is %q, rule wants %q", symbol, rule.Symbol), nil
+		}
+		alert.Reasons = append(alert.Reasons, fmt.Sprintf("symbol %s", symbol))
+	}
+
+	if rule.MinAmount != nil || rule.MaxAmount != nil {
+		amount, err := strconv.ParseFloat(tx.Amount, 64)
+		if err != nil {
+			return alert, fmt.Sprintf("amount %q is not numeric", tx.Amount), nil
+		}
+		if rule.MinAmount != nil && amount <= *rule.MinAmount {
+			return alert, fmt.Sprintf("amount %s is not above %g", tx.Amount, *rule.MinAmount), nil
+		}
+		if rule.MaxAmount != nil && amount >= *rule.MaxAmount {
+			return alert, fmt.Sprintf("amount %s is not below %g", tx.Amount, *rule.MaxAmount), nil
+		}
+		alert.Reasons = append(alert.Reasons, fmt.Sprintf("amount %s", tx.Amount))
+	}
+
+	if rule.CounterpartyLabel != "" {
+		counterparty := Address(tx.From)
+		if event.Direction == DirectionOutgoing {
+			counterparty = Address(tx.To)
+		}
+		labels, err := e.Labels(counterparty)
+		if err != nil {
+			return alert, "", err
+		}
+		alert.Labels = labels
+		if !containsLabel(labels, rule.CounterpartyLabel) {
+			return alert, fmt.Sprintf("counterparty %s is not labeled %q", counterparty, rule.CounterpartyLabel), nil
+		}
+		alert.Reasons = append(alert.Reasons, fmt.Sprintf("counterparty %s labeled %q", counterparty, rule.CounterpartyLabel))
+	}
+
+	if rule.BalanceBelow != nil {
+		balance, err := e.Balance(event.Address)
+		if err != nil {
+			return alert, "", err
+		}
+		alert.Balance = &balance
+		if balance >= *rule.BalanceBelow {
+			return alert, fmt.Sprintf("balance %g is not below %g", balance, *rule.BalanceBelow), nil
+		}
+		alert.Reasons = append(alert.Reasons, fmt.Sprintf("balance %g below %g", balance, *rule.BalanceBelow))
+	}
+
+	return alert, "", nil
+}
+
+func (e *RuleEngine) prune(now time.Time) {
+	window := e.RuleSet.DedupWindow
+	if window <= 0 {
+		window = defaultDedupWindow
+	}
+	for key, at := range e.fired {
+		if now.Sub(at) > window {
+			delete(e.fired, key)
+		}
+	}
+	for key, until := range e.silenced {
+		if !now.Before(until) {
+			delete(e.silenced, key)
+		}
+	}
+}
+
+func containsAddress(addresses []Address, address Address) bool {
+	for _, candidate := range addresses {
+		if strings.EqualFold(string(candidate), string(address)) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesProtocol(tx AddressTransaction, protocolType ProtocolType) bool {
+	if tx.TokenContractAddress == "" {
+		return false
+	}
+	if protocolType == Token20 {
+		return tx.TokenId == ""
+	}
+	return tx.TokenId != ""
+}
+
+func containsLabel(labels []string, want string) bool {
+	for _, label := range labels {
+		if strings.Contains(strings.ToLower(label), strings.ToLower(want)) {
+			return true
+		}
+	}
+	return false
+}
+
+func entityLabels(address Address) ([]string, error) {
+	response, err := AddressEntityLabels(address)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := decodeData[[]EntityLabel](response.Data)
+	if err != nil {
+		return nil, err
+	}
+	var labels []string
+	for _, entry := range entries {
+		if entry.Label != "" {
+			labels = append(labels, entry.Label)
+		}
+	}
+	return labels, nil
+}
+
+func nativeBalance(address Address) (float64, error) {
+	response, err := AddressInfo(address)
+	if err != nil {
+		return 0, err
+	}
+	if response.Data.Balance == "" {
+		return 0, errors.New("address summary returned no balance")
+	}
+	return strconv.ParseFloat(response.Data.Balance, 64)
+}
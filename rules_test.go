@@ -0,0 +1,138 @@
+package oklink
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testRules = `
+rules:
+  - name: large-usdt-in
+    direction: incoming
+    protocolType: token_20
+    symbol: USDT
+    minAmount: 10000
+  - name: to-exchange
+    direction: outgoing
+    counterpartyLabel: exchange
+    silence: 1h
+  - name: low-balance
+    balanceBelow: 5
+`
+
+func setupRuleEngine(t *testing.T, now *time.Time) *RuleEngine {
+	ruleSet, err := LoadRules(strings.NewReader(testRules))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	engine, err := NewRuleEngine(ruleSet)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	engine.Labels = func(address Address) ([]string, error) {
+		if address == "0xexchange" {
+			return []string{"Binance Exchange.Hot Wallet"}, nil
+		}
+		return nil, nil
+	}
+	engine.Balance = func(address Address) (float64, error) { return 100, nil }
+	engine.Now = func() time.Time { return *now }
+	return engine
+}
+
+func ruleEvent(direction Direction, tx AddressTransaction) WatchEvent {
+	return WatchEvent{Type: EventTransactionConfirmed, Address: "0xwallet", Direction: direction, Transaction: tx}
+}
+
+func TestLoadRulesRejectsUnknownFields(t *testing.T) {
+	_, err := LoadRules(strings.NewReader("rules:\n  - name: typo\n    minAmuont: 5\n"))
+	if err == nil {
+		t.Fatal("Expected error for unknown rule field, got nil")
+	}
+}
+
+func TestRuleEngineMatchesTokenTransfer(t *testing.T) {
+	now := time.Now()
+	engine := setupRuleEngine(t, &now)
+
+	tx := AddressTransaction{TxId: "0x1", From: "0xabc", To: "0xwallet", Amount: "25000", TransactionSymbol: "USDT", TokenContractAddress: "0xusdt"}
+	alerts, err := engine.Evaluate(ruleEvent(DirectionIncoming, tx))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].Rule != "large-usdt-in" {
+		t.Fatalf("Expected large-usdt-in alert, got %+v", alerts)
+	}
+
+	alerts, err = engine.Evaluate(ruleEvent(DirectionIncoming, tx))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Errorf("Expected duplicate event to be suppressed, got %d alerts", len(alerts))
+	}
+}
+
+func TestRuleEngineSilencesRule(t *testing.T) {
+	now := time.Now()
+	engine := setupRuleEngine(t, &now)
+
+	first := AddressTransaction{TxId: "0x1", From: "0xwallet", To: "0xexchange", Amount: "1"}
+	second := AddressTransaction{TxId: "0x2", From: "0xwallet", To: "0xexchange", Amount: "1"}
+
+	alerts, _ := engine.Evaluate(ruleEvent(DirectionOutgoing, first))
+	if len(alerts) != 1 || alerts[0].Rule != "to-exchange" {
+		t.Fatalf("Expected to-exchange alert, got %+v", alerts)
+	}
+
+	alerts, _ = engine.Evaluate(ruleEvent(DirectionOutgoing, second))
+	if len(alerts) != 0 {
+		t.Errorf("Expected silenced rule, got %d alerts", len(alerts))
+	}
+
+	now = now.Add(2 * time.Hour)
+	alerts, _ = engine.Evaluate(ruleEvent(DirectionOutgoing, second))
+	if len(alerts) != 1 {
+		t.Errorf("Expected alert after silence window, got %d alerts", len(alerts))
+	}
+}
+
+func TestRuleEngineTestReportsReasons(t *testing.T) {
+	now := time.Now()
+	engine := setupRuleEngine(t, &now)
+
+	tx := AddressTransaction{TxId: "0x1", From: "0xabc", To: "0xwallet", Amount: "50", TransactionSymbol: "USDT", TokenContractAddress: "0xusdt"}
+	results, err := engine.Test([]WatchEvent{ruleEvent(DirectionIncoming, tx)})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected one result per rule, got %d", len(results))
+	}
+	if results[0].Matched || !strings.Contains(results[0].Reason, "not above") {
+		t.Errorf("Expected amount threshold miss, got %+v", results[0])
+	}
+	if results[2].Matched {
+		t.Errorf("Expected balance rule to miss, got %+v", results[2])
+	}
+}
+
+func TestRuleEngineKeepsAlertsWhenLaterRuleFails(t *testing.T) {
+	now := time.Now()
+	engine := setupRuleEngine(t, &now)
+	engine.Labels = func(address Address) ([]string, error) { return nil, errors.New("labels unavailable") }
+
+	tx := AddressTransaction{TxId: "0x1", From: "0xwallet", To: "0xexchange", Amount: "25000", TransactionSymbol: "USDT", TokenContractAddress: "0xusdt"}
+	event := ruleEvent(DirectionOutgoing, tx)
+	engine.RuleSet.Rules = append([]Rule{{Name: "any-out", Direction: DirectionOutgoing}}, engine.RuleSet.Rules...)
+
+	alerts, err := engine.Evaluate(event)
+	if err == nil || !strings.Contains(err.Error(), "to-exchange") {
+		t.Fatalf("Expected error from to-exchange rule, got %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].Rule != "any-out" {
+		t.Fatalf("Expected any-out alert alongside the error, got %+v", alerts)
+	}
+}
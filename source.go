@@ -0,0 +1,373 @@
+package oklink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var ErrNotFound = errors.New("not found")
+
+// DataSource is where the higher-level helpers get chain data from. A source that cannot serve a call,
+// such as an RPC node asked for an address history, returns an error wrapping errors.ErrUnsupported.
+type DataSource interface {
+	Name() string
+	AddressBalance(ctx context.Context, address Address) (*AddressData, error)
+	TransactionDetails(ctx context.Context, txId string) (*TransactionFills, error)
+	BlockDetails(ctx context.Context, height string) (*BlockFills, error)
+	AddressTransactions(ctx context.Context, address Address, query TransactionQuery) (*AddressTransactionPage, error)
+	AddressTokenTransfers(ctx context.Context, address Address, protocolType ProtocolType, query TransactionQuery) (*AddressTransactionPage, error)
+	AddressTokenBalances(ctx context.Context, address Address, protocolType ProtocolType, page *string, limit *string) (*TokenBalancePage, error)
+	BatchBalances(ctx context.Context, addresses []Address) ([]AddressBalance, error)
+	BatchTransactions(ctx context.Context, addresses []Address, query TransactionQuery) (*BatchTransactionPage, error)
+	BatchTransactionDetails(ctx context.Context, txIds []string) ([]TransactionFills, error)
+	TokenPositions(ctx context.Context, tokenContractAddress Address, page *string, limit *string) (*TokenPositionPage, error)
+}
+
+type OKLinkSource struct{}
+
+var (
+	_ DataSource = (*OKLinkSource)(nil)
+	_ DataSource = (*RPCSource)(nil)
+	_ DataSource = (*FallbackSource)(nil)
+)
+
+type RPCSource struct {
+	Client *RPCClient
+	Symbol string
+}
+
+type FallbackSource struct {
+	Sources    []DataSource
+	OnFallback func(source DataSource, err error)
+}
+
+func NewOKLinkSource() *OKLinkSource {
+	return &OKLinkSource{}
+}
+
+func (s *OKLinkSource) Name() string { return "oklink" }
+
+func (s *OKLinkSource) AddressBalance(ctx context.Context, address Address) (*AddressData, error) {
+	response, err := AddressInfoContext(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	return &response.Data, nil
+}
+
+func (s *OKLinkSource) TransactionDetails(ctx context.Context, txId string) (*TransactionFills, error) {
+	tx, err := transactionFills(ctx, txId)
+	if err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		return nil, fmt.Errorf("transaction %s: %w", txId, ErrNotFound)
+	}
+	return tx, nil
+}
+
+func (s *OKLinkSource) BlockDetails(ctx context.Context, height string) (*BlockFills, error) {
+	response, err := BlockDetailsContext(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Data) == 0 {
+		return nil, fmt.Errorf("block %s: %w", height, ErrNotFound)
+	}
+	return &response.Data[0], nil
+}
+
+func NewRPCSource(url string) *RPCSource {
+	return &RPCSource{Client: NewRPCClient(url), Symbol: "KAIA"}
+}
+
+func (s *RPCSource) Name() string { return "rpc" }
+
+func (s *RPCSource) AddressBalance(ctx context.Context, address Address) (*AddressData, error) {
+	balance, err := s.Client.Balance(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	return &AddressData{
+		ChainFullName:  CHAIN_FULLNAME,
+		ChainShortName: CHAIN_SHORTNAME,
+		Address:        string(address),
+		Balance:        formatUnits(balance, nativeDecimals),
+		BalanceSymbol:  s.Symbol,
+	}, nil
+}
+
+func (s *RPCSource) TransactionDetails(ctx context.Context, txId string) (*TransactionFills, error) {
+	tx, err := s.Client.transaction(ctx, txId)
+	if err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		return nil, fmt.Errorf("transaction %s: %w", txId, ErrNotFound)
+	}
+
+	value, _ := parseQuantity(tx.Value)
+	gasPrice, _ := parseQuantity(tx.GasPrice)
+	details := &TransactionFills{
+		ChainFullName:     CHAIN_FULLNAME,
+		ChainShortName:    CHAIN_SHORTNAME,
+		TxId:              tx.Hash,
+		Amount:            formatUnits(value, nativeDecimals),
+		TransactionSymbol: s.Symbol,
+		InputDetails:      []TransactionEndpoint{{InputHash: tx.From, Amount: formatUnits(value, nativeDecimals)}},
+		OutputDetails:     []TransactionEndpoint{{OutputHash: tx.To, Amount: formatUnits(value, nativeDecimals)}},
+		State:             "pending",
+		GasLimit:          quantityString(tx.Gas),
+		GasPrice:          formatUnits(gasPrice, nativeDecimals),
+		Nonce:             quantityString(tx.Nonce),
+		InputData:         tx.Input,
+	}
+	if len(tx.Input) >= 10 {
+		details.MethodId = tx.Input[:10]
+	}
+	if tx.BlockNumber == "" {
+		return details, nil
+	}
+
+	receipt, err := s.Client.receipt(ctx, txId)
+	if err != nil {
+		return nil, err
+	}
+	if receipt == nil {
+		return details, nil
+	}
+	if details.OutputDetails[0].OutputHash == "" {
+		details.OutputDetails[0].OutputHash = receipt.ContractAddress
+	}
+	details.State = "success"
+	if receipt.Status != "0x1" {
+		details.State = "fail"
+		details.ErrorLog = receipt.TxError
+	}
+	gasUsed, _ := parseQuantity(receipt.GasUsed)
+	details.GasUsed = gasUsed.String()
+	if receipt.EffectiveGasPrice != "" {
+		gasPrice, _ = parseQuantity(receipt.EffectiveGasPrice)
+	}
+	details.TxFee = formatUnits(new(big.Int).Mul(gasUsed, gasPrice), nativeDecimals)
+
+	height, err := parseQuantity(tx.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	details.Height = height.String()
+	block, err := s.Client.block(ctx, tx.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if block != nil {
+		details.TransactionTime = blockTimeMillis(block.Timestamp)
+	}
+	latest, err := s.Client.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	details.Confirm = strconv.FormatInt(confirmations(latest, height.Int64()), 10)
+	return details, nil
+}
+
+func (s *RPCSource) BlockDetails(ctx context.Context, height string) (*BlockFills, error) {
+	number, err := toQuantity(height)
+	if err != nil {
+		return nil, err
+	}
+	block, err := s.Client.block(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %s: %w", height, ErrNotFound)
+	}
+
+	baseFee, _ := parseQuantity(block.BaseFeePerGas)
+	validator := block.Proposer
+	if validator == "" {
+		validator = block.Miner
+	}
+	details := &BlockFills{
+		ChainFullName:  CHAIN_FULLNAME,
+		ChainShortName: CHAIN_SHORTNAME,
+		Hash:           block.Hash,
+		Height:         quantityString(block.Number),
+		Validator:      validator,
+		Miner:          validator,
+		BlockTime:      blockTimeMillis(block.Timestamp),
+		TxnCount:       strconv.Itoa(len(block.Transactions)),
+		BlockSize:      quantityString(block.Size),
+		GasUsed:        quantityString(block.GasUsed),
+		GasLimit:       quantityString(block.GasLimit),
+		BaseFeePerGas:  formatUnits(baseFee, nativeDecimals),
+		FeeSymbol:      s.Symbol,
+	}
+	latest, err := s.Client.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	blockHeight, _ := strconv.ParseInt(details.Height, 10, 64)
+	details.Confirm = strconv.FormatInt(confirmations(latest, blockHeight), 10)
+	return details, nil
+}
+
+// BatchBalances looks the addresses up one by one, as nodes have no batch balance call.
+func (s *RPCSource) BatchBalances(ctx context.Context, addresses []Address) ([]AddressBalance, error) {
+	balances := make([]AddressBalance, 0, len(addresses))
+	for _, address := range addresses {
+		balance, err := s.AddressBalance(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, AddressBalance{Address: balance.Address, Balance: balance.Balance})
+	}
+	return balances, nil
+}
+
+// BatchTransactionDetails looks the transactions up one by one, leaving out the ones the node does not know.
+func (s *RPCSource) BatchTransactionDetails(ctx context.Context, txIds []string) ([]TransactionFills, error) {
+	var txs []TransactionFills
+	for _, txId := range txIds {
+		tx, err := s.TransactionDetails(ctx, txId)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, *tx)
+	}
+	return txs, nil
+}
+
+func (s *RPCSource) AddressTransactions(ctx context.Context, address Address, query TransactionQuery) (*AddressTransactionPage, error) {
+	return nil, unsupported(s, "address transaction lists")
+}
+
+func (s *RPCSource) AddressTokenTransfers(ctx context.Context, address Address, protocolType ProtocolType, query TransactionQuery) (*AddressTransactionPage, error) {
+	return nil, unsupported(s, "token transfer lists")
+}
+
+func (s *RPCSource) AddressTokenBalances(ctx context.Context, address Address, protocolType ProtocolType, page *string, limit *string) (*TokenBalancePage, error) {
+	return nil, unsupported(s, "token balance lists")
+}
+
+func (s *RPCSource) BatchTransactions(ctx context.Context, addresses []Address, query TransactionQuery) (*BatchTransactionPage, error) {
+	return nil, unsupported(s, "address transaction lists")
+}
+
+func (s *RPCSource) TokenPositions(ctx context.Context, tokenContractAddress Address, page *string, limit *string) (*TokenPositionPage, error) {
+	return nil, unsupported(s, "token holder lists")
+}
+
+func unsupported(source DataSource, what string) error {
+	return fmt.Errorf("%s source cannot serve %s: %w", source.Name(), what, errors.ErrUnsupported)
+}
+
+func NewFallbackSource(sources ...DataSource) *FallbackSource {
+	return &FallbackSource{Sources: sources}
+}
+
+func (s *FallbackSource) Name() string {
+	names := make([]string, len(s.Sources))
+	for i, source := range s.Sources {
+		names[i] = source.Name()
+	}
+	return "fallback(" + strings.Join(names, ",") + ")"
+}
+
+func (s *FallbackSource) AddressBalance(ctx context.Context, address Address) (*AddressData, error) {
+	return fallback(s, func(source DataSource) (*AddressData, error) { return source.AddressBalance(ctx, address) })
+}
+
+func (s *FallbackSource) TransactionDetails(ctx context.Context, txId string) (*TransactionFills, error) {
+	return fallback(s, func(source DataSource) (*TransactionFills, error) { return source.TransactionDetails(ctx, txId) })
+}
+
+func (s *FallbackSource) BlockDetails(ctx context.Context, height string) (*BlockFills, error) {
+	return fallback(s, func(source DataSource) (*BlockFills, error) { return source.BlockDetails(ctx, height) })
+}
+
+func (s *FallbackSource) AddressTransactions(ctx context.Context, address Address, query TransactionQuery) (*AddressTransactionPage, error) {
+	return fallback(s, func(source DataSource) (*AddressTransactionPage, error) {
+		return source.AddressTransactions(ctx, address, query)
+	})
+}
+
+func (s *FallbackSource) AddressTokenTransfers(ctx context.Context, address Address, protocolType ProtocolType, query TransactionQuery) (*AddressTransactionPage, error) {
+	return fallback(s, func(source DataSource) (*AddressTransactionPage, error) {
+		return source.AddressTokenTransfers(ctx, address, protocolType, query)
+	})
+}
+
+func (s *FallbackSource) AddressTokenBalances(ctx context.Context, address Address, protocolType ProtocolType, page *string, limit *string) (*TokenBalancePage, error) {
+	return fallback(s, func(source DataSource) (*TokenBalancePage, error) {
+		return source.AddressTokenBalances(ctx, address, protocolType, page, limit)
+	})
+}
+
+func (s *FallbackSource) BatchBalances(ctx context.Context, addresses []Address) ([]AddressBalance, error) {
+	balances, err := fallback(s, func(source DataSource) (*[]AddressBalance, error) {
+		balances, err := source.BatchBalances(ctx, addresses)
+		return &balances, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return *balances, nil
+}
+
+func (s *FallbackSource) BatchTransactions(ctx context.Context, addresses []Address, query TransactionQuery) (*BatchTransactionPage, error) {
+	return fallback(s, func(source DataSource) (*BatchTransactionPage, error) {
+		return source.BatchTransactions(ctx, addresses, query)
+	})
+}
+
+func (s *FallbackSource) BatchTransactionDetails(ctx context.Context, txIds []string) ([]TransactionFills, error) {
+	txs, err := fallback(s, func(source DataSource) (*[]TransactionFills, error) {
+		txs, err := source.BatchTransactionDetails(ctx, txIds)
+		return &txs, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return *txs, nil
+}
+
+func (s *FallbackSource) TokenPositions(ctx context.Context, tokenContractAddress Address, page *string, limit *string) (*TokenPositionPage, error) {
+	return fallback(s, func(source DataSource) (*TokenPositionPage, error) {
+		return source.TokenPositions(ctx, tokenContractAddress, page, limit)
+	})
+}
+
+func fallback[T any](s *FallbackSource, call func(DataSource) (*T, error)) (*T, error) {
+	if len(s.Sources) == 0 {
+		return nil, errors.New("fallback source has no sources")
+	}
+	var errs []error
+	for _, source := range s.Sources {
+		result, err := call(source)
+		if err == nil {
+			return result, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", source.Name(), err))
+		if s.OnFallback != nil {
+			s.OnFallback(source, err)
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+func blockTimeMillis(timestamp string) string {
+	seconds, err := parseQuantity(timestamp)
+	if err != nil {
+		return ""
+	}
+	return strconv.FormatInt(seconds.Int64()*1000, 10)
+}
@@ -0,0 +1,92 @@
+package oklink
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestRPCSourceTransactionDetails(t *testing.T) {
+	server, _ := setupRPCServer(map[string]any{
+		"kaia_getTransactionByHash": map[string]any{
+			"hash": "0xtx", "blockNumber": "0x64", "from": "0xfrom", "to": "0xto",
+			"gas": "0x5208", "gasPrice": "0x5d21dba00", "input": "0xa9059cbb0000", "nonce": "0x7", "value": "0xde0b6b3a7640000",
+		},
+		"kaia_getTransactionReceipt": map[string]any{"status": "0x1", "gasUsed": "0x5208", "effectiveGasPrice": "0x5d21dba00"},
+		"kaia_getBlockByNumber":      map[string]any{"number": "0x64", "timestamp": "0x6553f100"},
+		"kaia_blockNumber":           "0x6d",
+	})
+	defer server.Close()
+
+	tx, err := NewRPCSource(server.URL).TransactionDetails(context.Background(), "0xtx")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if tx.Height != "100" || tx.Confirm != "10" || tx.State != "success" || tx.Nonce != "7" {
+		t.Errorf("Unexpected transaction %+v", tx)
+	}
+	if tx.Amount != "1" || tx.TxFee != "0.000525" || tx.MethodId != "0xa9059cbb" {
+		t.Errorf("Unexpected amounts %s/%s/%s", tx.Amount, tx.TxFee, tx.MethodId)
+	}
+	if tx.TransactionTime != "1700000000000" || tx.OutputDetails[0].OutputHash != "0xto" {
+		t.Errorf("Unexpected time or recipient %+v", tx)
+	}
+}
+
+func TestRPCSourceNotFound(t *testing.T) {
+	server, _ := setupRPCServer(map[string]any{"kaia_getTransactionByHash": nil, "kaia_getBlockByNumber": nil})
+	defer server.Close()
+
+	source := NewRPCSource(server.URL)
+	if _, err := source.TransactionDetails(context.Background(), "0xmissing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if _, err := source.BlockDetails(context.Background(), "5"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestFallbackSource(t *testing.T) {
+	oklink := setupMockServer(`{"code": 50011, "data": [], "msg": "Too Many Requests"}`, http.StatusOK)
+	defer oklink.Close()
+	BASE_URL = oklink.URL + "/"
+
+	node, _ := setupRPCServer(map[string]any{"kaia_getBalance": "0x14d1120d7b160000"})
+	defer node.Close()
+
+	var failed []string
+	source := NewFallbackSource(NewOKLinkSource(), NewRPCSource(node.URL))
+	source.OnFallback = func(s DataSource, err error) { failed = append(failed, s.Name()) }
+
+	balance, err := source.AddressBalance(context.Background(), "0xabc")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if balance.Balance != "1.5" || balance.BalanceSymbol != "KAIA" {
+		t.Errorf("Expected 1.5 KAIA from the node, got %+v", balance)
+	}
+	if len(failed) != 1 || failed[0] != "oklink" {
+		t.Errorf("Expected oklink to fail over, got %v", failed)
+	}
+}
+
+func TestFallbackSourceSkipsUnsupportedCalls(t *testing.T) {
+	mockResponse := `{"code": 0, "data": [{"page": "1", "totalPage": "1", "transactionLists": [{"txId": "0xindexed"}]}], "msg": ""}`
+	oklink := setupMockServer(mockResponse, http.StatusOK)
+	defer oklink.Close()
+	BASE_URL = oklink.URL + "/"
+
+	node := NewRPCSource("http://127.0.0.1:0")
+	if _, err := node.AddressTransactions(context.Background(), "0xabc", TransactionQuery{}); !errors.Is(err, errors.ErrUnsupported) {
+		t.Fatalf("Expected errors.ErrUnsupported, got %v", err)
+	}
+
+	page, err := NewFallbackSource(node, NewOKLinkSource()).AddressTransactions(context.Background(), "0xabc", TransactionQuery{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(page.TransactionLists) != 1 || page.TransactionLists[0].TxId != "0xindexed" {
+		t.Errorf("Expected the OKLink page, got %+v", page)
+	}
+}
@@ -0,0 +1,114 @@
+package oklink
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const tracerName = "github.com/PaulElisha/oklink-kaiachain-sdk-go"
+
+var (
+	tracerMu sync.RWMutex
+	tracer   trace.Tracer
+)
+
+// EnableTracing records a span for every endpoint call and retry attempt using provider.
+// Passing nil turns tracing off again.
+func EnableTracing(provider trace.TracerProvider) {
+	tracerMu.Lock()
+	defer tracerMu.Unlock()
+	if provider == nil {
+		tracer = nil
+		return
+	}
+	tracer = provider.Tracer(tracerName)
+}
+
+// startSpan returns a no-op span, leaving ctx untouched, while tracing is off.
+func startSpan(ctx context.Context, name string, kind trace.SpanKind, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	tracerMu.RLock()
+	current := tracer
+	tracerMu.RUnlock()
+	if current == nil {
+		return ctx, noop.Span{}
+	}
+	return current.Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+}
+
+func startCallSpan(ctx context.Context, rawURL string) (context.Context, trace.Span) {
+	if !tracingEnabled() {
+		return ctx, noop.Span{}
+	}
+	_, endpoint, params, err := cacheKey(rawURL)
+	if err != nil {
+		return startSpan(ctx, "oklink", trace.SpanKindInternal)
+	}
+	return startSpan(ctx, "oklink "+endpoint, trace.SpanKindInternal, requestAttributes(endpoint, params)...)
+}
+
+// startPageSpan wraps a paginated fetch so the calls for each page share one parent.
+func startPageSpan(ctx context.Context, endpoint string) (context.Context, trace.Span) {
+	return startSpan(ctx, "oklink.paginate "+endpoint, trace.SpanKindInternal, attribute.String("oklink.endpoint", endpoint))
+}
+
+// startBatchSpan wraps one chunk of a request that was split to fit the endpoint's batch limit.
+func startBatchSpan(ctx context.Context, endpoint string, index int, size int) (context.Context, trace.Span) {
+	return startSpan(ctx, "oklink.batch "+endpoint, trace.SpanKindInternal,
+		attribute.String("oklink.endpoint", endpoint),
+		attribute.Int("oklink.batch.index", index),
+		attribute.Int("oklink.batch.size", size))
+}
+
+func tracingEnabled() bool {
+	tracerMu.RLock()
+	defer tracerMu.RUnlock()
+	return tracer != nil
+}
+
+func requestAttributes(endpoint string, params url.Values) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{attribute.String("oklink.endpoint", endpoint), attribute.String("oklink.chain", chainParam(params))}
+	if page := params.Get("page"); page != "" {
+		attrs = append(attrs, attribute.String("oklink.page", page))
+	}
+	return attrs
+}
+
+func chainParam(params url.Values) string {
+	for _, key := range []string{"chainShortName", "CHAIN_SHORTNAME", "chainId"} {
+		if value := params.Get(key); value != "" {
+			return value
+		}
+	}
+	return CHAIN_SHORTNAME
+}
+
+func statusAttribute(status int) attribute.KeyValue {
+	return attribute.Int("http.response.status_code", status)
+}
+
+// endSpan records the OKLink code and error on span and ends it.
+func endSpan(span trace.Span, err error) {
+	if !span.IsRecording() {
+		span.End()
+		return
+	}
+	var apiErr *APIError
+	switch {
+	case err == nil:
+		span.SetAttributes(attribute.Int("oklink.code", 0))
+	case errors.As(err, &apiErr):
+		span.SetAttributes(attribute.Int("oklink.code", apiErr.Code))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
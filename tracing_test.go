@@ -0,0 +1,155 @@
+package oklink
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setupTracing(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	EnableTracing(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { EnableTracing(nil) })
+	return recorder
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key string) (attribute.Value, bool) {
+	for _, attr := range span.Attributes() {
+		if string(attr.Key) == key {
+			return attr.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func spansNamed(recorder *tracetest.SpanRecorder, name string) []sdktrace.ReadOnlySpan {
+	var spans []sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.Name() == name {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+func TestTracingPaginationSpans(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		fmt.Fprintf(w, `{"code": 0, "data": [{"page": "%s", "limit": "100", "totalPage": "2", "blockList": [{"txid": "0x%s"}]}], "msg": ""}`, page, page)
+	}))
+	defer server.Close()
+	BASE_URL = server.URL + "/"
+	recorder := setupTracing(t)
+
+	txs, err := BlockTransactionListAllContext(context.Background(), "200", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(txs) != 2 {
+		t.Fatalf("Expected 2 transactions, got %d", len(txs))
+	}
+
+	parents := spansNamed(recorder, "oklink.paginate block/transaction-list")
+	if len(parents) != 1 {
+		t.Fatalf("Expected one pagination span, got %d", len(parents))
+	}
+	calls := spansNamed(recorder, "oklink block/transaction-list")
+	if len(calls) != 2 {
+		t.Fatalf("Expected a span per page, got %d", len(calls))
+	}
+	for i, call := range calls {
+		if call.Parent().SpanID() != parents[0].SpanContext().SpanID() {
+			t.Errorf("Expected page %d to be a child of the pagination span", i+1)
+		}
+		if page, _ := spanAttribute(call, "oklink.page"); page.AsString() != fmt.Sprint(i+1) {
+			t.Errorf("Expected page %d attribute, got %q", i+1, page.AsString())
+		}
+		if chain, _ := spanAttribute(call, "oklink.chain"); chain.AsString() != CHAIN_SHORTNAME {
+			t.Errorf("Expected chain attribute, got %q", chain.AsString())
+		}
+		if status, _ := spanAttribute(call, "http.response.status_code"); status.AsInt64() != http.StatusOK {
+			t.Errorf("Expected status attribute 200, got %d", status.AsInt64())
+		}
+		if code, ok := spanAttribute(call, "oklink.code"); !ok || code.AsInt64() != 0 {
+			t.Errorf("Expected code attribute 0, got %v", code)
+		}
+	}
+}
+
+func TestTracingRetryAttemptSpans(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"code": 50011, "data": null, "msg": "Too many requests"}`))
+	}))
+	defer server.Close()
+	BASE_URL = server.URL + "/"
+	recorder := setupTracing(t)
+	defer ResetMiddleware()
+	Use(Retry(2, time.Millisecond))
+
+	if _, err := AddressInfo("0xtraced"); err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	call := spansNamed(recorder, "oklink address/address-summary")
+	if len(call) != 1 {
+		t.Fatalf("Expected one call span, got %d", len(call))
+	}
+	if code, _ := spanAttribute(call[0], "oklink.code"); code.AsInt64() != 50011 {
+		t.Errorf("Expected code attribute 50011, got %d", code.AsInt64())
+	}
+	attempts := spansNamed(recorder, "oklink.attempt")
+	if len(attempts) != 2 {
+		t.Fatalf("Expected two attempt spans, got %d", len(attempts))
+	}
+	for i, attempt := range attempts {
+		if attempt.Parent().SpanID() != call[0].SpanContext().SpanID() {
+			t.Errorf("Expected attempt %d to be a child of the call span", i+1)
+		}
+		if number, _ := spanAttribute(attempt, "oklink.attempt"); number.AsInt64() != int64(i+1) {
+			t.Errorf("Expected attempt number %d, got %d", i+1, number.AsInt64())
+		}
+	}
+	if status, _ := spanAttribute(attempts[0], "http.response.status_code"); status.AsInt64() != http.StatusBadGateway {
+		t.Errorf("Expected first attempt status 502, got %d", status.AsInt64())
+	}
+}
+
+func TestTracingBatchChunkSpans(t *testing.T) {
+	server := setupMockServer(`{"code": 0, "data": [], "msg": ""}`, http.StatusOK)
+	defer server.Close()
+	BASE_URL = server.URL + "/"
+	recorder := setupTracing(t)
+
+	tokens := make([]Address, maxPriceTokens+1)
+	for i := range tokens {
+		tokens[i] = Address(fmt.Sprintf("0x%040x", i))
+	}
+	if _, err := NewPriceCache(time.Hour).PricesContext(context.Background(), tokens); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	batches := spansNamed(recorder, "oklink.batch tokenprice/price-multi")
+	if len(batches) != 2 {
+		t.Fatalf("Expected two chunk spans, got %d", len(batches))
+	}
+	if size, _ := spanAttribute(batches[1], "oklink.batch.size"); size.AsInt64() != 1 {
+		t.Errorf("Expected the second chunk to hold 1 token, got %d", size.AsInt64())
+	}
+	calls := spansNamed(recorder, "oklink tokenprice/price-multi")
+	if len(calls) != 2 || calls[1].Parent().SpanID() != batches[1].SpanContext().SpanID() {
+		t.Errorf("Expected each call to be a child of its chunk span")
+	}
+}
@@ -0,0 +1,95 @@
+package oklink
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// MaxResponseBytes caps how much of a response body is read; larger responses fail instead of exhausting memory.
+var MaxResponseBytes int64 = 32 << 20
+
+var httpClient = &http.Client{Transport: newTransport(), Timeout: 30 * time.Second}
+
+var bufferPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+const maxPooledBuffer = 1 << 20
+
+// SetHTTPClient replaces the shared client used for every OKLink request.
+func SetHTTPClient(client *http.Client) {
+	httpClient = client
+}
+
+func newTransport() *http.Transport {
+	// Leaving Accept-Encoding unset lets the transport negotiate gzip and decompress transparently.
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   32,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
+// fetchInto decodes the response while it streams in, copying the raw body into buf when buf is non-nil.
+// It also returns the HTTP status, or 0 when no response arrived.
+func fetchInto[T any](req *http.Request, buf *bytes.Buffer) (*ApiResponse[T], int, error) {
+	response, err := httpClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error making request: %w", err)
+	}
+	defer response.Body.Close()
+	status := response.StatusCode
+	if status != http.StatusOK {
+		io.Copy(io.Discard, io.LimitReader(response.Body, 4096))
+		return nil, status, &HTTPError{StatusCode: status}
+	}
+	if response.ContentLength > MaxResponseBytes {
+		return nil, status, fmt.Errorf("response body of %d bytes exceeds the %d byte limit", response.ContentLength, MaxResponseBytes)
+	}
+
+	body := &io.LimitedReader{R: response.Body, N: MaxResponseBytes + 1}
+	var reader io.Reader = body
+	if buf != nil {
+		if response.ContentLength > 0 {
+			buf.Grow(int(response.ContentLength))
+		}
+		reader = io.TeeReader(body, buf)
+	}
+	decoded, err := decodeResponse[T](reader)
+	if body.N <= 0 {
+		return nil, status, fmt.Errorf("response body exceeds the %d byte limit", MaxResponseBytes)
+	}
+	if err != nil {
+		return nil, status, err
+	}
+	// Draining the rest keeps the connection reusable.
+	io.Copy(io.Discard, reader)
+	if body.N <= 0 {
+		return nil, status, fmt.Errorf("response body exceeds the %d byte limit", MaxResponseBytes)
+	}
+	return decoded, status, nil
+}
+
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBuffer {
+		return
+	}
+	bufferPool.Put(buf)
+}
@@ -0,0 +1,110 @@
+package oklink
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func transactionPageBody(count int) string {
+	txs := make([]string, count)
+	for i := range txs {
+		txs[i] = fmt.Sprintf(`{"txId": "0x%064x", "blockHash": "0x%064x", "height": "%d", "transactionTime": "1700000000000", "from": "0x%040x", "to": "0x%040x", "amount": "1.5", "transactionSymbol": "KAIA", "txFee": "0.000525", "state": "success"}`, i, i, 100+i, i, i+1)
+	}
+	return `{"code": 0, "data": [{"page": "1", "limit": "100", "totalPage": "9", "transactionLists": [` + strings.Join(txs, ",") + `]}], "msg": ""}`
+}
+
+func TestFetchApiDecodesGzip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			t.Errorf("Expected gzip to be negotiated, got %q", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		writer := gzip.NewWriter(w)
+		io.WriteString(writer, `{"code": 0, "data": {"balance": "7"}, "msg": ""}`)
+		writer.Close()
+	}))
+	defer server.Close()
+	BASE_URL = server.URL + "/"
+
+	response, err := AddressInfo("0xgzip")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Data.Balance != "7" {
+		t.Errorf("Expected balance 7, got %s", response.Data.Balance)
+	}
+}
+
+func TestFetchApiEnforcesMaxResponseBytes(t *testing.T) {
+	body := transactionPageBody(10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Flushing first forces a chunked response, so the limit is enforced while reading.
+		w.(http.Flusher).Flush()
+		io.WriteString(w, body)
+	}))
+	defer server.Close()
+	BASE_URL = server.URL + "/"
+
+	previous := MaxResponseBytes
+	MaxResponseBytes = int64(len(body) / 2)
+	defer func() { MaxResponseBytes = previous }()
+
+	if _, err := AddressInfo("0xlarge"); err == nil || !strings.Contains(err.Error(), "byte limit") {
+		t.Errorf("Expected the body limit error, got %v", err)
+	}
+}
+
+func BenchmarkFetchApiTransactionPage(b *testing.B) {
+	body := transactionPageBody(100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, body)
+	}))
+	defer server.Close()
+	url := server.URL + "/api/v5/explorer/address/transaction-list"
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(body)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := fetchApi[[]AddressTransactionPage](url); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkFetchApiTransactionPageUnpooled measures the previous approach of a fresh client and io.ReadAll per call.
+func BenchmarkFetchApiTransactionPageUnpooled(b *testing.B) {
+	body := transactionPageBody(100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, body)
+	}))
+	defer server.Close()
+	url := server.URL + "/api/v5/explorer/address/transaction-list"
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(body)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		client := &http.Client{Transport: &http.Transport{}}
+		response, err := client.Get(url)
+		if err != nil {
+			b.Fatal(err)
+		}
+		raw, err := io.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			b.Fatal(err)
+		}
+		var decoded ApiResponse[[]AddressTransactionPage]
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			b.Fatal(err)
+		}
+		client.CloseIdleConnections()
+	}
+}
@@ -0,0 +1,283 @@
+package oklink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Severity string
+
+const (
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+type DiscrepancyKind string
+
+const (
+	DiscrepancyBalance      DiscrepancyKind = "balance"
+	DiscrepancyTransaction  DiscrepancyKind = "transaction"
+	DiscrepancyTokenBalance DiscrepancyKind = "token_balance"
+)
+
+type Discrepancy struct {
+	Kind            DiscrepancyKind
+	Severity        Severity
+	Subject         string
+	Field           string
+	Primary         string
+	Secondary       string
+	PrimaryHeight   int64
+	SecondaryHeight int64
+	DetectedAt      time.Time
+}
+
+type HeightSource interface {
+	LatestHeight(ctx context.Context) (int64, error)
+}
+
+type TokenBalanceSource interface {
+	TokenBalance(ctx context.Context, tokenContractAddress Address, holderAddress Address) (string, error)
+}
+
+type TokenPositionPage struct {
+	PageInfo
+	ChainFullName     string          `json:"chainFullName"`
+	ChainShortName    string          `json:"chainShortName"`
+	CirculatingSupply string          `json:"circulatingSupply"`
+	PositionList      []TokenPosition `json:"positionList"`
+}
+
+type TokenPosition struct {
+	HolderAddress     string `json:"holderAddress"`
+	Amount            string `json:"amount"`
+	ValueUsd          string `json:"valueUsd"`
+	PositionChange24h string `json:"positionChange24h"`
+	Rank              string `json:"rank"`
+}
+
+type Verifier struct {
+	Primary   DataSource
+	Secondary DataSource
+	// Tolerance is the relative difference between amounts that is still treated as equal.
+	Tolerance float64
+	// LagBlocks is how far apart the sources may be before a mismatch is no longer blamed on lag.
+	LagBlocks int64
+	Now       func() time.Time
+}
+
+type verifyHeights struct {
+	primary   int64
+	secondary int64
+}
+
+const (
+	balanceOfSelector = "0x70a08231"
+	decimalsSelector  = "0x313ce567"
+	maxTokenDecimals  = 77
+)
+
+func NewVerifier(primary DataSource, secondary DataSource) *Verifier {
+	return &Verifier{Primary: primary, Secondary: secondary, LagBlocks: 3}
+}
+
+func (v *Verifier) VerifyBalance(ctx context.Context, address Address) ([]Discrepancy, error) {
+	heights := v.heights(ctx)
+	primary, err := v.Primary.AddressBalance(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching %s balance from %s: %w", address, v.Primary.Name(), err)
+	}
+	secondary, err := v.Secondary.AddressBalance(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching %s balance from %s: %w", address, v.Secondary.Name(), err)
+	}
+
+	var discrepancies []Discrepancy
+	if !v.amountsMatch(primary.Balance, secondary.Balance) {
+		discrepancies = append(discrepancies, v.discrepancy(DiscrepancyBalance, v.lagSeverity(heights), string(address), "balance", primary.Balance, secondary.Balance, heights))
+	}
+	return discrepancies, nil
+}
+
+func (v *Verifier) VerifyTransaction(ctx context.Context, txId string) ([]Discrepancy, error) {
+	heights := v.heights(ctx)
+	primary, primaryErr := v.Primary.TransactionDetails(ctx, txId)
+	if primaryErr != nil && !errors.Is(primaryErr, ErrNotFound) {
+		return nil, fmt.Errorf("error fetching transaction %s from %s: %w", txId, v.Primary.Name(), primaryErr)
+	}
+	secondary, secondaryErr := v.Secondary.TransactionDetails(ctx, txId)
+	if secondaryErr != nil && !errors.Is(secondaryErr, ErrNotFound) {
+		return nil, fmt.Errorf("error fetching transaction %s from %s: %w", txId, v.Secondary.Name(), secondaryErr)
+	}
+
+	switch {
+	case primary == nil && secondary == nil:
+		return nil, nil
+	case primary == nil:
+		return []Discrepancy{v.discrepancy(DiscrepancyTransaction, v.missingSeverity(secondary), txId, "existence", "missing", secondary.State, heights)}, nil
+	case secondary == nil:
+		return []Discrepancy{v.discrepancy(DiscrepancyTransaction, v.missingSeverity(primary), txId, "existence", primary.State, "missing", heights)}, nil
+	}
+
+	var discrepancies []Discrepancy
+	// A pending transaction on one side is lag, anything else disagreeing about the outcome is not.
+	if !strings.EqualFold(primary.State, secondary.State) {
+		severity := SeverityCritical
+		if isPendingState(primary.State) || isPendingState(secondary.State) {
+			severity = SeverityWarning
+		}
+		discrepancies = append(discrepancies, v.discrepancy(DiscrepancyTransaction, severity, txId, "state", primary.State, secondary.State, heights))
+	}
+	if !v.amountsMatch(primary.Amount, secondary.Amount) {
+		discrepancies = append(discrepancies, v.discrepancy(DiscrepancyTransaction, SeverityCritical, txId, "amount", primary.Amount, secondary.Amount, heights))
+	}
+	if primary.Height != "" && secondary.Height != "" && primary.Height != secondary.Height {
+		discrepancies = append(discrepancies, v.discrepancy(DiscrepancyTransaction, SeverityCritical, txId, "height", primary.Height, secondary.Height, heights))
+	}
+	return discrepancies, nil
+}
+
+func (v *Verifier) VerifyTokenPositions(ctx context.Context, tokenContractAddress Address, page *string, limit *string) ([]Discrepancy, error) {
+	balances, ok := v.Secondary.(TokenBalanceSource)
+	if !ok {
+		return nil, fmt.Errorf("%s cannot report token balances", v.Secondary.Name())
+	}
+	heights := v.heights(ctx)
+	positions, err := v.Primary.TokenPositions(ctx, tokenContractAddress, page, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching positions for %s from %s: %w", tokenContractAddress, v.Primary.Name(), err)
+	}
+
+	var discrepancies []Discrepancy
+	for _, position := range positions.PositionList {
+		balance, err := balances.TokenBalance(ctx, tokenContractAddress, Address(position.HolderAddress))
+		if err != nil {
+			return discrepancies, fmt.Errorf("error fetching %s balance of %s: %w", tokenContractAddress, position.HolderAddress, err)
+		}
+		if !v.amountsMatch(position.Amount, balance) {
+			subject := position.HolderAddress + "@" + string(tokenContractAddress)
+			discrepancies = append(discrepancies, v.discrepancy(DiscrepancyTokenBalance, v.lagSeverity(heights), subject, "amount", position.Amount, balance, heights))
+		}
+	}
+	return discrepancies, nil
+}
+
+func (v *Verifier) heights(ctx context.Context) verifyHeights {
+	var heights verifyHeights
+	// Heights are best effort; a source that cannot report one is recorded as 0.
+	if source, ok := v.Primary.(HeightSource); ok {
+		heights.primary, _ = source.LatestHeight(ctx)
+	}
+	if source, ok := v.Secondary.(HeightSource); ok {
+		heights.secondary, _ = source.LatestHeight(ctx)
+	}
+	return heights
+}
+
+func (v *Verifier) lagSeverity(heights verifyHeights) Severity {
+	if heights.primary == 0 || heights.secondary == 0 {
+		return SeverityWarning
+	}
+	lag := heights.primary - heights.secondary
+	if lag < 0 {
+		lag = -lag
+	}
+	if lag > 0 && lag <= v.LagBlocks {
+		return SeverityWarning
+	}
+	return SeverityCritical
+}
+
+func (v *Verifier) missingSeverity(found *TransactionFills) Severity {
+	confirm, _ := strconv.ParseInt(found.Confirm, 10, 64)
+	if isPendingState(found.State) || confirm <= v.LagBlocks {
+		return SeverityWarning
+	}
+	return SeverityCritical
+}
+
+func (v *Verifier) amountsMatch(a string, b string) bool {
+	left, okLeft := new(big.Rat).SetString(strings.TrimSpace(a))
+	right, okRight := new(big.Rat).SetString(strings.TrimSpace(b))
+	if !okLeft || !okRight {
+		return strings.TrimSpace(a) == strings.TrimSpace(b)
+	}
+	if left.Cmp(right) == 0 {
+		return true
+	}
+	if v.Tolerance <= 0 {
+		return false
+	}
+	diff := new(big.Rat).Sub(left, right)
+	diff.Abs(diff)
+	largest := new(big.Rat).Abs(left)
+	if abs := new(big.Rat).Abs(right); abs.Cmp(largest) > 0 {
+		largest = abs
+	}
+	limit := new(big.Rat).Mul(largest, new(big.Rat).SetFloat64(v.Tolerance))
+	return diff.Cmp(limit) <= 0
+}
+
+func (v *Verifier) discrepancy(kind DiscrepancyKind, severity Severity, subject string, field string, primary string, secondary string, heights verifyHeights) Discrepancy {
+	now := time.Now()
+	if v.Now != nil {
+		now = v.Now()
+	}
+	return Discrepancy{
+		Kind:            kind,
+		Severity:        severity,
+		Subject:         subject,
+		Field:           field,
+		Primary:         primary,
+		Secondary:       secondary,
+		PrimaryHeight:   heights.primary,
+		SecondaryHeight: heights.secondary,
+		DetectedAt:      now,
+	}
+}
+
+func isPendingState(state string) bool {
+	return state == "" || strings.EqualFold(state, "pending")
+}
+
+func (s *OKLinkSource) LatestHeight(ctx context.Context) (int64, error) {
+	return latestBlockHeightContext(ctx)
+}
+
+func (s *RPCSource) LatestHeight(ctx context.Context) (int64, error) {
+	return s.Client.BlockNumber(ctx)
+}
+
+func (s *RPCSource) TokenBalance(ctx context.Context, tokenContractAddress Address, holderAddress Address) (string, error) {
+	holder, err := decodeHex(string(holderAddress))
+	if err != nil || len(holder) > 32 {
+		return "", fmt.Errorf("invalid holder address %s", holderAddress)
+	}
+	data := balanceOfSelector + fmt.Sprintf("%064x", new(big.Int).SetBytes(holder))
+	balance, err := s.call(ctx, tokenContractAddress, data)
+	if err != nil {
+		return "", fmt.Errorf("error calling balanceOf: %w", err)
+	}
+	decimals, err := s.call(ctx, tokenContractAddress, decimalsSelector)
+	if err != nil {
+		return "", fmt.Errorf("error calling decimals: %w", err)
+	}
+	// uint256 amounts have at most 78 digits, so anything above 77 decimals is not a real token.
+	if decimals.Sign() < 0 || decimals.Cmp(big.NewInt(maxTokenDecimals)) > 0 {
+		return "", fmt.Errorf("token %s reports %s decimals", tokenContractAddress, decimals)
+	}
+	return formatUnits(balance, int(decimals.Int64())), nil
+}
+
+func (s *RPCSource) call(ctx context.Context, to Address, data string) (*big.Int, error) {
+	var result string
+	if err := s.Client.Call(ctx, "call", &result, map[string]string{"to": string(to), "data": data}, "latest"); err != nil {
+		return nil, err
+	}
+	return parseQuantity(result)
+}
@@ -0,0 +1,131 @@
+package oklink
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func setupVerifyOKLinkServer(lastHeight string, summary string, transaction string, positions string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/blockchain/summary"):
+			w.Write([]byte(`{"code": 0, "data": [{"lastHeight": "` + lastHeight + `"}], "msg": ""}`))
+		case strings.HasSuffix(r.URL.Path, "/address/address-summary"):
+			w.Write([]byte(`{"code": 0, "data": ` + summary + `, "msg": ""}`))
+		case strings.HasSuffix(r.URL.Path, "/transaction/transaction-fills"):
+			w.Write([]byte(`{"code": 0, "data": [` + transaction + `], "msg": ""}`))
+		case strings.HasSuffix(r.URL.Path, "/token/position-list"):
+			w.Write([]byte(`{"code": 0, "data": [{"positionList": [` + positions + `]}], "msg": ""}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestVerifyBalanceLagIsWarning(t *testing.T) {
+	oklink := setupVerifyOKLinkServer("108", `{"balance": "1.4"}`, "", "")
+	defer oklink.Close()
+	BASE_URL = oklink.URL + "/"
+
+	node, _ := setupRPCServer(map[string]any{"kaia_getBalance": "0x14d1120d7b160000", "kaia_blockNumber": "0x6e"})
+	defer node.Close()
+
+	discrepancies, err := NewVerifier(NewOKLinkSource(), NewRPCSource(node.URL)).VerifyBalance(context.Background(), "0xabc")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(discrepancies) != 1 {
+		t.Fatalf("Expected one discrepancy, got %+v", discrepancies)
+	}
+	d := discrepancies[0]
+	if d.Severity != SeverityWarning || d.Primary != "1.4" || d.Secondary != "1.5" || d.PrimaryHeight != 108 || d.SecondaryHeight != 110 {
+		t.Errorf("Unexpected discrepancy %+v", d)
+	}
+}
+
+func TestVerifyBalanceWithinTolerance(t *testing.T) {
+	oklink := setupVerifyOKLinkServer("110", `{"balance": "1.4999999"}`, "", "")
+	defer oklink.Close()
+	BASE_URL = oklink.URL + "/"
+
+	node, _ := setupRPCServer(map[string]any{"kaia_getBalance": "0x14d1120d7b160000", "kaia_blockNumber": "0x6e"})
+	defer node.Close()
+
+	verifier := NewVerifier(NewOKLinkSource(), NewRPCSource(node.URL))
+	verifier.Tolerance = 0.001
+	discrepancies, err := verifier.VerifyBalance(context.Background(), "0xabc")
+	if err != nil || len(discrepancies) != 0 {
+		t.Errorf("Expected no discrepancies, got %+v, %v", discrepancies, err)
+	}
+}
+
+func TestVerifyTransactionStateMismatch(t *testing.T) {
+	oklink := setupVerifyOKLinkServer("110", `{}`, `{"txid": "0xtx", "height": "100", "state": "success", "amount": "1"}`, "")
+	defer oklink.Close()
+	BASE_URL = oklink.URL + "/"
+
+	node, _ := setupRPCServer(map[string]any{
+		"kaia_getTransactionByHash":  map[string]any{"hash": "0xtx", "blockNumber": "0x64", "value": "0xde0b6b3a7640000"},
+		"kaia_getTransactionReceipt": map[string]any{"status": "0x0", "gasUsed": "0x0"},
+		"kaia_getBlockByNumber":      map[string]any{"number": "0x64", "timestamp": "0x0"},
+		"kaia_blockNumber":           "0x6e",
+	})
+	defer node.Close()
+
+	discrepancies, err := NewVerifier(NewOKLinkSource(), NewRPCSource(node.URL)).VerifyTransaction(context.Background(), "0xtx")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(discrepancies) != 1 || discrepancies[0].Field != "state" || discrepancies[0].Severity != SeverityCritical {
+		t.Errorf("Expected a critical state discrepancy, got %+v", discrepancies)
+	}
+}
+
+func TestVerifyTokenPositions(t *testing.T) {
+	oklink := setupVerifyOKLinkServer("110", `{}`, "", `{"holderAddress": "0x00000000000000000000000000000000000000aa", "amount": "2.5"}`)
+	defer oklink.Close()
+	BASE_URL = oklink.URL + "/"
+
+	node, _ := setupRPCServer(map[string]any{
+		"kaia_call":        "0x0000000000000000000000000000000000000000000000000000000000000006",
+		"kaia_blockNumber": "0x6e",
+	})
+	defer node.Close()
+
+	discrepancies, err := NewVerifier(NewOKLinkSource(), NewRPCSource(node.URL)).VerifyTokenPositions(context.Background(), "0xtoken", nil, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(discrepancies) != 1 || discrepancies[0].Kind != DiscrepancyTokenBalance || discrepancies[0].Secondary != "0.000006" {
+		t.Errorf("Unexpected discrepancies %+v", discrepancies)
+	}
+	if discrepancies[0].Severity != SeverityCritical {
+		t.Errorf("Expected matching heights to make the mismatch critical, got %s", discrepancies[0].Severity)
+	}
+}
+
+func TestVerifyTokenPositionsUsesPrimary(t *testing.T) {
+	node, _ := setupRPCServer(map[string]any{"kaia_blockNumber": "0x6e"})
+	defer node.Close()
+
+	_, err := NewVerifier(NewRPCSource(node.URL), NewRPCSource(node.URL)).VerifyTokenPositions(context.Background(), "0xtoken", nil, nil)
+	if !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("Expected the primary's unsupported error, got %v", err)
+	}
+}
+
+func TestRPCSourceRejectsOutOfRangeDecimals(t *testing.T) {
+	node, _ := setupRPCServer(map[string]any{
+		"kaia_call": "0x000000000000000000000000000000000000000000000000000000000000004e",
+	})
+	defer node.Close()
+
+	_, err := NewRPCSource(node.URL).TokenBalance(context.Background(), "0xtoken", "0x00000000000000000000000000000000000000aa")
+	if err == nil || !strings.Contains(err.Error(), "78 decimals") {
+		t.Errorf("Expected 78 decimals to be rejected, got %v", err)
+	}
+}
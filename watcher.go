@@ -0,0 +1,467 @@
+package oklink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type WatchEventType string
+
+const (
+	EventTransactionSeen      WatchEventType = "transaction_seen"
+	EventTransactionConfirmed WatchEventType = "transaction_confirmed"
+)
+
+type Direction string
+
+const (
+	DirectionIncoming Direction = "incoming"
+	DirectionOutgoing Direction = "outgoing"
+	DirectionSelf     Direction = "self"
+)
+
+type WatchEvent struct {
+	Type          WatchEventType
+	Address       Address
+	Direction     Direction
+	Transaction   AddressTransaction
+	Height        int64
+	Confirmations int64
+	ObservedAt    time.Time
+}
+
+type Watcher struct {
+	Addresses     []Address
+	Interval      time.Duration
+	Confirmations int64
+	ProtocolType  *ProtocolType
+	EmitExisting  bool
+	MaxPages      int
+	// BatchLookback is how many blocks before the head are scanned the first time an address is polled in
+	// batch mode.
+	BatchLookback int64
+	OnEvent       func(WatchEvent)
+	OnError       func(error)
+	LatestHeight  func() (int64, error)
+	Source        DataSource
+
+	mu      sync.Mutex
+	events  chan WatchEvent
+	seen    map[string]int64
+	pending map[string]WatchEvent
+	// lastHeight is the chain head each address was last fully scanned up to in batch mode.
+	lastHeight map[Address]int64
+	// cursors resume batch height windows that needed more than MaxPages pages.
+	cursors map[string]batchCursor
+	seeded  bool
+}
+
+type batchCursor struct {
+	start int64
+	end   int64
+	page  int
+}
+
+type batchProgress struct {
+	key    string
+	chunk  []Address
+	cursor batchCursor
+	done   bool
+}
+
+type watchUpdate struct {
+	key   string
+	event WatchEvent
+}
+
+const (
+	watcherPageLimit  = 50
+	watcherBatchLimit = 50
+	// One hour of Kaia blocks.
+	watcherBatchLookback = 3600
+)
+
+func NewWatcher(addresses []Address, interval time.Duration) *Watcher {
+	return &Watcher{
+		Addresses:     addresses,
+		Interval:      interval,
+		Confirmations: 1,
+		MaxPages:      5,
+		BatchLookback: watcherBatchLookback,
+		events:        make(chan WatchEvent, 64),
+		seen:          map[string]int64{},
+		pending:       map[string]WatchEvent{},
+		lastHeight:    map[Address]int64{},
+		cursors:       map[string]batchCursor{},
+	}
+}
+
+func (w *Watcher) Events() <-chan WatchEvent {
+	return w.events
+}
+
+func (w *Watcher) Run(ctx context.Context) error {
+	if len(w.Addresses) == 0 {
+		return errors.New("watcher has no addresses to watch")
+	}
+	if w.Interval <= 0 {
+		return errors.New("watcher interval must be positive")
+	}
+	defer close(w.events)
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		if err := w.Poll(ctx); err != nil && w.OnError != nil {
+			w.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Watcher) Poll(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var latest int64
+	var err error
+	if w.Confirmations > 1 || w.useBatch() {
+		if latest, err = w.latestHeight(); err != nil {
+			return err
+		}
+	}
+
+	var txs []AddressTransaction
+	var progress []batchProgress
+	if w.useBatch() {
+		txs, progress, err = w.fetchBatch(ctx, latest)
+	} else {
+		txs, err = w.fetchEach(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Events are worked out first and seen, pending and the scanned heights only change once they are
+	// emitted, so an emit that fails leaves the rest to be reported by the next poll.
+	var updates []watchUpdate
+	var fresh []watchUpdate
+	now := time.Now()
+	returned := make(map[string]bool, len(txs))
+	for _, tx := range txs {
+		height, _ := strconv.ParseInt(tx.Height, 10, 64)
+		for _, address := range w.Addresses {
+			direction, ok := transferDirection(address, tx)
+			if !ok {
+				continue
+			}
+			key := watchKey(address, tx)
+			if returned[key] {
+				continue
+			}
+			returned[key] = true
+			if _, ok := w.seen[key]; ok {
+				continue
+			}
+			if !w.EmitExisting && !w.seededFor(address) {
+				w.seen[key] = height
+				continue
+			}
+			event := WatchEvent{
+				Type:        EventTransactionSeen,
+				Address:     address,
+				Direction:   direction,
+				Transaction: tx,
+				Height:      height,
+				ObservedAt:  now,
+			}
+			fresh = append(fresh, watchUpdate{key: key, event: event})
+		}
+	}
+	updates = append(updates, fresh...)
+	for key, event := range w.pending {
+		if confirmed, ok := w.confirmed(event, latest, now); ok {
+			updates = append(updates, watchUpdate{key: key, event: confirmed})
+		}
+	}
+	for _, update := range fresh {
+		if confirmed, ok := w.confirmed(update.event, latest, now); ok {
+			updates = append(updates, watchUpdate{key: update.key, event: confirmed})
+		}
+	}
+
+	for _, update := range updates {
+		if err := w.emit(ctx, update.event); err != nil {
+			return err
+		}
+		if update.event.Type == EventTransactionSeen {
+			w.seen[update.key] = update.event.Height
+			w.pending[update.key] = update.event
+		} else {
+			delete(w.pending, update.key)
+		}
+	}
+	w.seeded = true
+	w.commitBatch(progress)
+	w.pruneSeen(returned, latest)
+	return nil
+}
+
+// confirmed turns a seen event into its confirmed event once it has enough confirmations at latest.
+func (w *Watcher) confirmed(event WatchEvent, latest int64, now time.Time) (WatchEvent, bool) {
+	event.Confirmations = confirmations(latest, event.Height)
+	if w.Confirmations > 1 && event.Confirmations < w.Confirmations {
+		return event, false
+	}
+	event.Type = EventTransactionConfirmed
+	event.ObservedAt = now
+	return event, true
+}
+
+// seededFor reports whether address has been scanned once, so its transactions are new rather than existing.
+func (w *Watcher) seededFor(address Address) bool {
+	if w.useBatch() {
+		_, ok := w.lastHeight[address]
+		return ok
+	}
+	return w.seeded
+}
+
+func (w *Watcher) useBatch() bool {
+	return len(w.Addresses) > 1 && w.ProtocolType == nil
+}
+
+func (w *Watcher) source() DataSource {
+	if w.Source != nil {
+		return w.Source
+	}
+	return NewOKLinkSource()
+}
+
+func (w *Watcher) latestHeight() (int64, error) {
+	if w.LatestHeight != nil {
+		return w.LatestHeight()
+	}
+	return latestBlockHeight()
+}
+
+func (w *Watcher) fetchEach(ctx context.Context) ([]AddressTransaction, error) {
+	var txs []AddressTransaction
+	for _, address := range w.Addresses {
+		addressTxs, err := w.fetchAddress(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, addressTxs...)
+	}
+	return txs, nil
+}
+
+func (w *Watcher) fetchAddress(ctx context.Context, address Address) (txs []AddressTransaction, err error) {
+	ctx, span := startPageSpan(ctx, "address/transaction-list")
+	defer func() { endSpan(span, err) }()
+
+	source := w.source()
+	limit := strconv.Itoa(watcherPageLimit)
+	for page := 1; page <= w.maxPages(); page++ {
+		pageNumber := strconv.Itoa(page)
+		result, err := source.AddressTransactions(ctx, address, TransactionQuery{ProtocolType: w.ProtocolType, Page: &pageNumber, Limit: &limit})
+		if err != nil {
+			return nil, fmt.Errorf("error polling address %s: %w", address, err)
+		}
+		txs = append(txs, result.TransactionLists...)
+		if len(result.TransactionLists) == 0 || !w.needsNextPage(address, result.PageInfo, result.TransactionLists) {
+			break
+		}
+	}
+	return txs, nil
+}
+
+func (w *Watcher) fetchBatch(ctx context.Context, latest int64) ([]AddressTransaction, []batchProgress, error) {
+	var txs []AddressTransaction
+	var progress []batchProgress
+	for i := 0; i < len(w.Addresses); i += watcherBatchLimit {
+		chunk := w.Addresses[i:min(i+watcherBatchLimit, len(w.Addresses))]
+		chunkTxs, chunkProgress, err := w.fetchChunk(ctx, i/watcherBatchLimit, chunk, latest)
+		if err != nil {
+			return nil, nil, err
+		}
+		txs = append(txs, chunkTxs...)
+		progress = append(progress, chunkProgress)
+	}
+	return txs, progress, nil
+}
+
+// fetchChunk scans up to MaxPages pages of the chunk's height window. A window with more pages is
+// resumed by the next poll, and only a finished window moves the chunk's addresses on to its end.
+func (w *Watcher) fetchChunk(ctx context.Context, index int, chunk []Address, latest int64) (txs []AddressTransaction, progress batchProgress, err error) {
+	ctx, span := startBatchSpan(ctx, "address/normal-transaction-list-multi", index, len(chunk))
+	defer func() { endSpan(span, err) }()
+
+	key := addressList(chunk)
+	cursor, ok := w.cursors[key]
+	if !ok {
+		cursor = batchCursor{start: w.chunkStartHeight(chunk, latest), end: latest, page: 1}
+	}
+	source := w.source()
+	limit := strconv.Itoa(watcherPageLimit)
+	start := strconv.FormatInt(cursor.start, 10)
+	end := strconv.FormatInt(cursor.end, 10)
+	for i := 0; i < w.maxPages(); i++ {
+		pageNumber := strconv.Itoa(cursor.page)
+		result, err := source.BatchTransactions(ctx, chunk, TransactionQuery{StartBlockHeight: &start, EndBlockHeight: &end, Page: &pageNumber, Limit: &limit})
+		if err != nil {
+			return nil, batchProgress{}, fmt.Errorf("error polling %d addresses: %w", len(chunk), err)
+		}
+		txs = append(txs, result.TransactionList...)
+		if !result.hasNext() {
+			return txs, batchProgress{key: key, chunk: chunk, cursor: cursor, done: true}, nil
+		}
+		cursor.page++
+	}
+	return txs, batchProgress{key: key, chunk: chunk, cursor: cursor}, nil
+}
+
+// commitBatch records how far each chunk got once its transactions have been reported.
+func (w *Watcher) commitBatch(progress []batchProgress) {
+	if !w.useBatch() {
+		return
+	}
+	cursors := map[string]batchCursor{}
+	for _, chunk := range progress {
+		if !chunk.done {
+			cursors[chunk.key] = chunk.cursor
+			continue
+		}
+		for _, address := range chunk.chunk {
+			w.lastHeight[address] = chunk.cursor.end
+		}
+	}
+	w.cursors = cursors
+}
+
+// chunkStartHeight starts the window at the oldest head any address in chunk was scanned up to, or
+// BatchLookback blocks back for an address that has not been scanned yet.
+func (w *Watcher) chunkStartHeight(chunk []Address, latest int64) int64 {
+	start := latest
+	for _, address := range chunk {
+		height, ok := w.lastHeight[address]
+		if !ok {
+			height = latest - w.BatchLookback
+		}
+		start = min(start, height)
+	}
+	return max(start, 0)
+}
+
+// scanFloor is the lowest height the next batch poll can return; nothing at or above it may be forgotten.
+func (w *Watcher) scanFloor(latest int64) int64 {
+	if !w.useBatch() {
+		return math.MaxInt64
+	}
+	floor := latest
+	for _, cursor := range w.cursors {
+		floor = min(floor, cursor.start)
+	}
+	for _, address := range w.Addresses {
+		height, ok := w.lastHeight[address]
+		if !ok {
+			height = latest - w.BatchLookback
+		}
+		floor = min(floor, height)
+	}
+	return floor
+}
+
+func (w *Watcher) needsNextPage(address Address, info PageInfo, txs []AddressTransaction) bool {
+	if !w.seeded || !info.hasNext() {
+		return false
+	}
+	for _, tx := range txs {
+		if _, ok := w.seen[watchKey(address, tx)]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// pruneSeen forgets confirmed transactions the last poll no longer returned. Both the batch height
+// window and the newest-first pages have moved past them, so they cannot be reported again.
+func (w *Watcher) pruneSeen(returned map[string]bool, latest int64) {
+	floor := w.scanFloor(latest)
+	for key, height := range w.seen {
+		if returned[key] || height >= floor {
+			continue
+		}
+		if _, ok := w.pending[key]; ok {
+			continue
+		}
+		if confirmations(latest, height) >= max(w.Confirmations, 1) {
+			delete(w.seen, key)
+		}
+	}
+}
+
+func (w *Watcher) maxPages() int {
+	if w.MaxPages <= 0 {
+		return 1
+	}
+	return w.MaxPages
+}
+
+func (w *Watcher) emit(ctx context.Context, event WatchEvent) error {
+	if w.OnEvent != nil {
+		w.OnEvent(event)
+		return nil
+	}
+	select {
+	case w.events <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p PageInfo) hasNext() bool {
+	page, _ := strconv.Atoi(p.Page)
+	total, _ := strconv.Atoi(p.TotalPage)
+	return page < total
+}
+
+func transferDirection(address Address, tx AddressTransaction) (Direction, bool) {
+	from := strings.EqualFold(tx.From, string(address))
+	to := strings.EqualFold(tx.To, string(address))
+	switch {
+	case from && to:
+		return DirectionSelf, true
+	case to:
+		return DirectionIncoming, true
+	case from:
+		return DirectionOutgoing, true
+	}
+	return "", false
+}
+
+func watchKey(address Address, tx AddressTransaction) string {
+	return strings.ToLower(strings.Join([]string{string(address), tx.TxId, tx.TokenContractAddress, tx.TokenId, tx.From, tx.To, tx.Amount}, "|"))
+}
+
+func confirmations(latest int64, height int64) int64 {
+	if latest == 0 {
+		latest = height
+	}
+	if latest < height || height == 0 {
+		return 0
+	}
+	return latest - height + 1
+}
@@ -0,0 +1,293 @@
+package oklink
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func setupWatcherServer(lastHeight *string, txs *[]string) *httptest.Server {
+	var mu sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/blockchain/summary"):
+			fmt.Fprintf(w, `{"code": 0, "data": [{"lastHeight": "%s"}], "msg": ""}`, *lastHeight)
+		case strings.HasSuffix(r.URL.Path, "/address/transaction-list"):
+			fmt.Fprintf(w, `{"code": 0, "data": [{"page": "1", "limit": "50", "totalPage": "1", "transactionLists": [%s]}], "msg": ""}`, strings.Join(*txs, ","))
+		case strings.HasSuffix(r.URL.Path, "/address/normal-transaction-list-multi"):
+			fmt.Fprintf(w, `{"code": 0, "data": [{"page": "1", "limit": "50", "totalPage": "1", "transactionList": [%s]}], "msg": ""}`, strings.Join(*txs, ","))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func watcherTx(txId string, from string, to string, height string) string {
+	return fmt.Sprintf(`{"txId": "%s", "from": "%s", "to": "%s", "height": "%s", "amount": "1"}`, txId, from, to, height)
+}
+
+func TestWatcherEmitsNewIncomingTransaction(t *testing.T) {
+	lastHeight := "100"
+	txs := []string{watcherTx("0xold", "0xaaa", "0xbbb", "90")}
+	server := setupWatcherServer(&lastHeight, &txs)
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	var events []WatchEvent
+	watcher := NewWatcher([]Address{"0xBBB"}, 0)
+	watcher.OnEvent = func(event WatchEvent) { events = append(events, event) }
+
+	if err := watcher.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("Expected existing transactions to be skipped, got %d events", len(events))
+	}
+
+	txs = append(txs, watcherTx("0xnew", "0xaaa", "0xbbb", "101"))
+	if err := watcher.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := watcher.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("Expected seen and confirmed events, got %d", len(events))
+	}
+	if events[0].Type != EventTransactionSeen || events[1].Type != EventTransactionConfirmed {
+		t.Errorf("Expected seen then confirmed, got %s then %s", events[0].Type, events[1].Type)
+	}
+	if events[0].Direction != DirectionIncoming {
+		t.Errorf("Expected incoming direction, got %s", events[0].Direction)
+	}
+	if events[0].Transaction.TxId != "0xnew" {
+		t.Errorf("Expected txId 0xnew, got %s", events[0].Transaction.TxId)
+	}
+}
+
+func TestWatcherWaitsForConfirmations(t *testing.T) {
+	lastHeight := "100"
+	txs := []string{}
+	server := setupWatcherServer(&lastHeight, &txs)
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	var events []WatchEvent
+	watcher := NewWatcher([]Address{"0xaaa", "0xbbb"}, 0)
+	watcher.Confirmations = 3
+	watcher.OnEvent = func(event WatchEvent) { events = append(events, event) }
+
+	if err := watcher.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	txs = append(txs, watcherTx("0xnew", "0xaaa", "0xbbb", "100"))
+	if err := watcher.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Expected outgoing and incoming seen events, got %d", len(events))
+	}
+	for _, event := range events {
+		if event.Type != EventTransactionSeen {
+			t.Errorf("Expected seen event before confirmations, got %s", event.Type)
+		}
+	}
+
+	lastHeight = "102"
+	if err := watcher.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(events) != 4 {
+		t.Fatalf("Expected confirmed events, got %d events", len(events))
+	}
+	if events[2].Type != EventTransactionConfirmed || events[2].Confirmations != 3 {
+		t.Errorf("Expected confirmed event with 3 confirmations, got %s with %d", events[2].Type, events[2].Confirmations)
+	}
+}
+
+func TestWatcherBatchScansBlocksBetweenPolls(t *testing.T) {
+	var mu sync.Mutex
+	lastHeight := int64(100)
+	type indexedTx struct {
+		height int64
+		json   string
+	}
+	var txs []indexedTx
+	var windows []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/blockchain/summary"):
+			fmt.Fprintf(w, `{"code": 0, "data": [{"lastHeight": "%d"}], "msg": ""}`, lastHeight)
+		case strings.HasSuffix(r.URL.Path, "/address/normal-transaction-list-multi"):
+			start, _ := strconv.ParseInt(r.URL.Query().Get("startBlockHeight"), 10, 64)
+			end, _ := strconv.ParseInt(r.URL.Query().Get("endBlockHeight"), 10, 64)
+			windows = append(windows, fmt.Sprintf("%d-%d", start, end))
+			var matched []string
+			for _, tx := range txs {
+				if tx.height >= start && tx.height <= end {
+					matched = append(matched, tx.json)
+				}
+			}
+			fmt.Fprintf(w, `{"code": 0, "data": [{"page": "1", "limit": "50", "totalPage": "1", "transactionList": [%s]}], "msg": ""}`, strings.Join(matched, ","))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	var events []WatchEvent
+	watcher := NewWatcher([]Address{"0xaaa", "0xbbb"}, 0)
+	watcher.OnEvent = func(event WatchEvent) { events = append(events, event) }
+
+	if err := watcher.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	mu.Lock()
+	txs = append(txs, indexedTx{103, watcherTx("0xmissed", "0xccc", "0xbbb", "103")})
+	lastHeight = 107
+	mu.Unlock()
+	if err := watcher.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(windows) != 2 || windows[1] != "100-107" {
+		t.Fatalf("Expected the second poll to scan from the previous head, got windows %v", windows)
+	}
+	if len(events) != 2 || events[0].Transaction.TxId != "0xmissed" || events[0].Type != EventTransactionSeen {
+		t.Fatalf("Expected seen and confirmed events for 0xmissed, got %+v", events)
+	}
+
+	mu.Lock()
+	lastHeight = 110
+	mu.Unlock()
+	if err := watcher.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(watcher.seen) != 0 {
+		t.Errorf("Expected confirmed transactions outside the window to be pruned, got %d seen", len(watcher.seen))
+	}
+	if len(events) != 2 {
+		t.Errorf("Expected no repeated events, got %d", len(events))
+	}
+}
+
+func TestWatcherBatchResumesTruncatedWindow(t *testing.T) {
+	var mu sync.Mutex
+	lastHeight := int64(100)
+	pages := map[string][]string{}
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/blockchain/summary"):
+			fmt.Fprintf(w, `{"code": 0, "data": [{"lastHeight": "%d"}], "msg": ""}`, lastHeight)
+		case strings.HasSuffix(r.URL.Path, "/address/normal-transaction-list-multi"):
+			query := r.URL.Query()
+			window := query.Get("startBlockHeight") + "-" + query.Get("endBlockHeight")
+			requests = append(requests, window+"/"+query.Get("page"))
+			txs := pages[window]
+			page, _ := strconv.Atoi(query.Get("page"))
+			var matched []string
+			if page <= len(txs) {
+				matched = append(matched, txs[page-1])
+			}
+			fmt.Fprintf(w, `{"code": 0, "data": [{"page": "%d", "limit": "1", "totalPage": "%d", "transactionList": [%s]}], "msg": ""}`, page, max(len(txs), 1), strings.Join(matched, ","))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	var events []WatchEvent
+	watcher := NewWatcher([]Address{"0xaaa", "0xbbb"}, 0)
+	watcher.MaxPages = 1
+	watcher.BatchLookback = 50
+	watcher.OnEvent = func(event WatchEvent) { events = append(events, event) }
+	poll := func(head int64) {
+		mu.Lock()
+		lastHeight = head
+		mu.Unlock()
+		if err := watcher.Poll(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	poll(100)
+	mu.Lock()
+	pages["100-107"] = []string{watcherTx("0xfirst", "0xccc", "0xaaa", "104"), watcherTx("0xsecond", "0xccc", "0xbbb", "103")}
+	mu.Unlock()
+	poll(107)
+	poll(110)
+	poll(112)
+
+	want := []string{"50-100/1", "100-107/1", "100-107/2", "107-112/1"}
+	if strings.Join(requests, " ") != strings.Join(want, " ") {
+		t.Errorf("Expected requests %v, got %v", want, requests)
+	}
+	var seen []string
+	for _, event := range events {
+		if event.Type == EventTransactionSeen {
+			seen = append(seen, event.Transaction.TxId)
+		}
+	}
+	if strings.Join(seen, ",") != "0xfirst,0xsecond" {
+		t.Errorf("Expected both transactions of the truncated window, got %v", seen)
+	}
+}
+
+func TestWatcherReemitsAfterFailedEmit(t *testing.T) {
+	lastHeight := "100"
+	txs := []string{}
+	server := setupWatcherServer(&lastHeight, &txs)
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	watcher := NewWatcher([]Address{"0xbbb"}, 0)
+	watcher.events = make(chan WatchEvent)
+	if err := watcher.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	txs = append(txs, watcherTx("0xnew", "0xaaa", "0xbbb", "100"))
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := watcher.Poll(cancelled); err == nil {
+		t.Fatal("Expected the emit to fail")
+	}
+
+	received := make(chan []WatchEvent)
+	go func() {
+		var events []WatchEvent
+		for i := 0; i < 2; i++ {
+			events = append(events, <-watcher.events)
+		}
+		received <- events
+	}()
+	if err := watcher.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	events := <-received
+	if events[0].Type != EventTransactionSeen || events[1].Type != EventTransactionConfirmed || events[0].Transaction.TxId != "0xnew" {
+		t.Errorf("Expected the unemitted events again, got %+v", events)
+	}
+}
@@ -0,0 +1,287 @@
+package oklink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type FlowClassification string
+
+const (
+	FlowExchangeInflow     FlowClassification = "exchange_inflow"
+	FlowExchangeOutflow    FlowClassification = "exchange_outflow"
+	FlowExchangeToExchange FlowClassification = "exchange_to_exchange"
+	FlowUnknownToUnknown   FlowClassification = "unknown_to_unknown"
+)
+
+var DefaultExchangeKeywords = []string{"exchange", "binance", "okx", "upbit", "bithumb", "coinbase", "bybit", "kraken", "kucoin", "huobi", "htx", "bitget", "coinone", "korbit"}
+
+type WhaleTransaction struct {
+	Transaction    LargeTransaction
+	Height         int64
+	Amount         float64
+	USDValue       *float64
+	FromLabels     []string
+	ToLabels       []string
+	Classification FlowClassification
+	ObservedAt     time.Time
+}
+
+type WhaleMonitor struct {
+	Interval         time.Duration
+	MinAmount        *string
+	MinUSDValue      float64
+	MaxBlocksPerPoll int64
+	ExchangeKeywords []string
+	FeedSize         int
+	Labels           func(Address) ([]string, error)
+	Price            func(symbol string) (float64, error)
+	LatestHeight     func() (int64, error)
+	OnTransaction    func(WhaleTransaction)
+	OnError          func(error)
+
+	mu         sync.Mutex
+	events     chan WhaleTransaction
+	nextHeight int64
+	labels     *MemoryCache
+	feedMu     sync.Mutex
+	feed       []WhaleTransaction
+	// emitted holds the txIds already emitted at nextHeight when a poll stopped partway through it.
+	emitted map[string]bool
+}
+
+const (
+	whalePageLimit      = 100
+	whaleLabelCacheSize = 10000
+	whaleLabelTTL       = time.Hour
+)
+
+func NewWhaleMonitor(interval time.Duration) *WhaleMonitor {
+	return &WhaleMonitor{
+		Interval:         interval,
+		MaxBlocksPerPoll: 50,
+		ExchangeKeywords: DefaultExchangeKeywords,
+		FeedSize:         100,
+		Labels:           entityLabels,
+		events:           make(chan WhaleTransaction, 64),
+		labels:           NewMemoryCache(whaleLabelCacheSize),
+	}
+}
+
+func (m *WhaleMonitor) Events() <-chan WhaleTransaction {
+	return m.events
+}
+
+func (m *WhaleMonitor) Feed() []WhaleTransaction {
+	m.feedMu.Lock()
+	defer m.feedMu.Unlock()
+	feed := make([]WhaleTransaction, len(m.feed))
+	copy(feed, m.feed)
+	return feed
+}
+
+func (m *WhaleMonitor) Run(ctx context.Context) error {
+	if m.Interval <= 0 {
+		return errors.New("whale monitor interval must be positive")
+	}
+	defer close(m.events)
+
+	ticker := time.NewTicker(m.Interval)
+	defer ticker.Stop()
+	for {
+		if err := m.Poll(ctx); err != nil && m.OnError != nil {
+			m.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll scans the blocks since the last poll. Price and label lookups that fail do not hold the monitor
+// back: the transaction is emitted without them and the errors are returned once the blocks are done.
+func (m *WhaleMonitor) Poll(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	latest, err := m.latestHeight()
+	if err != nil {
+		return err
+	}
+	if m.nextHeight == 0 {
+		m.nextHeight = latest
+	}
+
+	last := latest
+	if m.MaxBlocksPerPoll > 0 && last-m.nextHeight+1 > m.MaxBlocksPerPoll {
+		last = m.nextHeight + m.MaxBlocksPerPoll - 1
+	}
+	var errs []error
+	for height := m.nextHeight; height <= last; height++ {
+		txs, err := m.fetchHeight(ctx, height)
+		if err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		for _, tx := range txs {
+			key := strings.ToLower(tx.TxId)
+			if m.emitted[key] {
+				continue
+			}
+			whale, ok, enrichErrs := m.enrich(tx)
+			errs = append(errs, enrichErrs...)
+			if !ok {
+				continue
+			}
+			if err := m.emit(ctx, whale); err != nil {
+				return errors.Join(append(errs, err)...)
+			}
+			if m.emitted == nil {
+				m.emitted = map[string]bool{}
+			}
+			m.emitted[key] = true
+		}
+		m.nextHeight = height + 1
+		m.emitted = nil
+	}
+	return errors.Join(errs...)
+}
+
+func (m *WhaleMonitor) latestHeight() (int64, error) {
+	if m.LatestHeight != nil {
+		return m.LatestHeight()
+	}
+	return latestBlockHeight()
+}
+
+func (m *WhaleMonitor) fetchHeight(ctx context.Context, height int64) (txs []LargeTransaction, err error) {
+	ctx, span := startPageSpan(ctx, "transaction/large-transaction-list")
+	defer func() { endSpan(span, err) }()
+
+	heightValue := strconv.FormatInt(height, 10)
+	limit := strconv.Itoa(whalePageLimit)
+	for page := 1; ; page++ {
+		pageNumber := strconv.Itoa(page)
+		response, err := LargeTransactionListContext(ctx, m.MinAmount, &heightValue, &pageNumber, &limit)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching large transactions at height %d: %w", height, err)
+		}
+		pages, err := decodeData[[]LargeTransactionPage](response.Data)
+		if err != nil {
+			return nil, err
+		}
+		if len(pages) == 0 {
+			return txs, nil
+		}
+		txs = append(txs, pages[0].TransactionList...)
+		if !pages[0].hasNext() {
+			return txs, nil
+		}
+	}
+}
+
+// enrich prices and labels tx. A failed price lookup leaves USDValue nil and skips the MinUSDValue
+// filter, so an unpriceable transaction is still reported.
+func (m *WhaleMonitor) enrich(tx LargeTransaction) (WhaleTransaction, bool, []error) {
+	whale := WhaleTransaction{Transaction: tx, ObservedAt: time.Now()}
+	whale.Height, _ = strconv.ParseInt(tx.Height, 10, 64)
+	whale.Amount, _ = strconv.ParseFloat(tx.Amount, 64)
+
+	var errs []error
+	var unpriced bool
+	if m.Price != nil {
+		price, err := m.Price(tx.TransactionSymbol)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error pricing %s in tx %s: %w", tx.TransactionSymbol, tx.TxId, err))
+			unpriced = true
+		} else {
+			value := whale.Amount * price
+			whale.USDValue = &value
+		}
+	}
+	if m.MinUSDValue > 0 && !unpriced && (whale.USDValue == nil || *whale.USDValue < m.MinUSDValue) {
+		return whale, false, errs
+	}
+
+	var err error
+	if whale.FromLabels, err = m.addressLabels(Address(tx.From)); err != nil {
+		errs = append(errs, err)
+	}
+	if whale.ToLabels, err = m.addressLabels(Address(tx.To)); err != nil {
+		errs = append(errs, err)
+	}
+	whale.Classification = m.classify(whale.FromLabels, whale.ToLabels)
+	return whale, true, errs
+}
+
+func (m *WhaleMonitor) addressLabels(address Address) ([]string, error) {
+	key := strings.ToLower(string(address))
+	if key == "" {
+		return nil, nil
+	}
+	if cached, ok, _ := m.labels.Get(key); ok {
+		var labels []string
+		if err := json.Unmarshal(cached, &labels); err == nil {
+			return labels, nil
+		}
+	}
+	labels, err := m.Labels(address)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching labels for %s: %w", address, err)
+	}
+	if encoded, err := json.Marshal(labels); err == nil {
+		m.labels.Set(key, encoded, whaleLabelTTL)
+	}
+	return labels, nil
+}
+
+func (m *WhaleMonitor) classify(fromLabels []string, toLabels []string) FlowClassification {
+	fromExchange := m.isExchange(fromLabels)
+	toExchange := m.isExchange(toLabels)
+	switch {
+	case fromExchange && toExchange:
+		return FlowExchangeToExchange
+	case toExchange:
+		return FlowExchangeInflow
+	case fromExchange:
+		return FlowExchangeOutflow
+	}
+	return FlowUnknownToUnknown
+}
+
+func (m *WhaleMonitor) isExchange(labels []string) bool {
+	for _, keyword := range m.ExchangeKeywords {
+		if containsLabel(labels, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *WhaleMonitor) emit(ctx context.Context, whale WhaleTransaction) error {
+	if m.OnTransaction != nil {
+		m.OnTransaction(whale)
+	} else {
+		select {
+		case m.events <- whale:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if m.FeedSize > 0 {
+		m.feedMu.Lock()
+		m.feed = append(m.feed, whale)
+		if len(m.feed) > m.FeedSize {
+			m.feed = m.feed[len(m.feed)-m.FeedSize:]
+		}
+		m.feedMu.Unlock()
+	}
+	return nil
+}
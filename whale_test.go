@@ -0,0 +1,204 @@
+package oklink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLargeTransactionListPath(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte(`{"code": 0, "data": [], "msg": ""}`))
+	}))
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	if _, err := LargeTransactionList(nil, nil, nil, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if path != "/api/v5/explorer/transaction/large-transaction-list" {
+		t.Errorf("Expected large-transaction-list path, got %s", path)
+	}
+}
+
+func TestWhaleMonitorClassifiesTransactions(t *testing.T) {
+	lastHeight := "100"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/blockchain/summary"):
+			fmt.Fprintf(w, `{"code": 0, "data": [{"lastHeight": "%s"}], "msg": ""}`, lastHeight)
+		case strings.HasSuffix(r.URL.Path, "/large-transaction-list"):
+			if r.URL.Query().Get("height") != "101" {
+				w.Write([]byte(`{"code": 0, "data": [], "msg": ""}`))
+				return
+			}
+			w.Write([]byte(`{"code": 0, "data": [{"page": "1", "totalPage": "1", "transactionList": [
+				{"txid": "0xin", "height": "101", "from": "0xwhale", "to": "0xbinance", "amount": "500000", "transactionSymbol": "KAIA"},
+				{"txid": "0xout", "height": "101", "from": "0xbinance", "to": "0xwhale", "amount": "10", "transactionSymbol": "KAIA"},
+				{"txid": "0xdark", "height": "101", "from": "0xwhale", "to": "0xother", "amount": "300000", "transactionSymbol": "KAIA"}
+			]}], "msg": ""}`))
+		case strings.HasSuffix(r.URL.Path, "/entity-labels"):
+			if r.URL.Query().Get("address") == "0xbinance" {
+				w.Write([]byte(`{"code": 0, "data": [{"label": "Binance.Hot Wallet", "address": "0xbinance"}], "msg": ""}`))
+				return
+			}
+			w.Write([]byte(`{"code": 0, "data": [], "msg": ""}`))
+		}
+	}))
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	var whales []WhaleTransaction
+	monitor := NewWhaleMonitor(0)
+	monitor.MinUSDValue = 1000
+	monitor.Price = func(symbol string) (float64, error) { return 0.2, nil }
+	monitor.OnTransaction = func(whale WhaleTransaction) { whales = append(whales, whale) }
+
+	if err := monitor.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	lastHeight = "101"
+	if err := monitor.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(whales) != 2 {
+		t.Fatalf("Expected 2 whale transactions above the USD threshold, got %d", len(whales))
+	}
+	if whales[0].Classification != FlowExchangeInflow {
+		t.Errorf("Expected exchange inflow, got %s", whales[0].Classification)
+	}
+	if *whales[0].USDValue != 100000 {
+		t.Errorf("Expected USD value 100000, got %f", *whales[0].USDValue)
+	}
+	if whales[1].Classification != FlowUnknownToUnknown {
+		t.Errorf("Expected unknown to unknown, got %s", whales[1].Classification)
+	}
+	if feed := monitor.Feed(); len(feed) != 2 || feed[1].Transaction.TxId != "0xdark" {
+		t.Errorf("Expected rolling feed of 2 transactions, got %+v", feed)
+	}
+}
+
+func TestWhaleMonitorAdvancesPastFailedPrice(t *testing.T) {
+	lastHeight := "100"
+	var labelRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/blockchain/summary"):
+			fmt.Fprintf(w, `{"code": 0, "data": [{"lastHeight": "%s"}], "msg": ""}`, lastHeight)
+		case strings.HasSuffix(r.URL.Path, "/large-transaction-list"):
+			if r.URL.Query().Get("height") != "101" {
+				w.Write([]byte(`{"code": 0, "data": [], "msg": ""}`))
+				return
+			}
+			w.Write([]byte(`{"code": 0, "data": [{"page": "1", "totalPage": "1", "transactionList": [
+				{"txid": "0xpriced", "height": "101", "from": "0xwhale", "to": "0xother", "amount": "500000", "transactionSymbol": "KAIA"},
+				{"txid": "0xunknown", "height": "101", "from": "0xwhale", "to": "0xother", "amount": "10", "transactionSymbol": "ODD"}
+			]}], "msg": ""}`))
+		case strings.HasSuffix(r.URL.Path, "/entity-labels"):
+			labelRequests++
+			w.Write([]byte(`{"code": 0, "data": [], "msg": ""}`))
+		}
+	}))
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	var whales []WhaleTransaction
+	monitor := NewWhaleMonitor(0)
+	monitor.MinUSDValue = 1000
+	monitor.Price = func(symbol string) (float64, error) {
+		if symbol == "ODD" {
+			return 0, errors.New("no price")
+		}
+		return 0.2, nil
+	}
+	monitor.OnTransaction = func(whale WhaleTransaction) { whales = append(whales, whale) }
+
+	if err := monitor.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	lastHeight = "101"
+	err := monitor.Poll(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "error pricing ODD") {
+		t.Fatalf("Expected pricing error, got %v", err)
+	}
+	if len(whales) != 2 {
+		t.Fatalf("Expected both transactions to be emitted, got %d", len(whales))
+	}
+	if whales[1].Transaction.TxId != "0xunknown" || whales[1].USDValue != nil {
+		t.Errorf("Expected unpriced transaction without USD value, got %+v", whales[1])
+	}
+
+	if err := monitor.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected no error once past the height, got %v", err)
+	}
+	if len(whales) != 2 {
+		t.Errorf("Expected no repeated transactions, got %d", len(whales))
+	}
+	if labelRequests != 2 {
+		t.Errorf("Expected labels to be cached per address, got %d lookups", labelRequests)
+	}
+}
+
+func TestWhaleMonitorDoesNotReemitAfterFailedEmit(t *testing.T) {
+	lastHeight := "100"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/blockchain/summary"):
+			fmt.Fprintf(w, `{"code": 0, "data": [{"lastHeight": "%s"}], "msg": ""}`, lastHeight)
+		case strings.HasSuffix(r.URL.Path, "/large-transaction-list"):
+			if r.URL.Query().Get("height") != "101" {
+				w.Write([]byte(`{"code": 0, "data": [], "msg": ""}`))
+				return
+			}
+			w.Write([]byte(`{"code": 0, "data": [{"page": "1", "totalPage": "1", "transactionList": [
+				{"txid": "0xfirst", "height": "101", "from": "0xwhale", "to": "0xother", "amount": "500000", "transactionSymbol": "KAIA"},
+				{"txid": "0xsecond", "height": "101", "from": "0xwhale", "to": "0xother", "amount": "600000", "transactionSymbol": "KAIA"}
+			]}], "msg": ""}`))
+		case strings.HasSuffix(r.URL.Path, "/entity-labels"):
+			w.Write([]byte(`{"code": 0, "data": [], "msg": ""}`))
+		}
+	}))
+	defer server.Close()
+
+	BASE_URL = server.URL + "/"
+
+	monitor := NewWhaleMonitor(0)
+	monitor.events = make(chan WhaleTransaction, 1)
+	if err := monitor.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	lastHeight = "101"
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := monitor.Poll(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the second emit to time out, got %v", err)
+	}
+	if first := <-monitor.events; first.Transaction.TxId != "0xfirst" {
+		t.Fatalf("Expected 0xfirst, got %s", first.Transaction.TxId)
+	}
+
+	if err := monitor.Poll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if second := <-monitor.events; second.Transaction.TxId != "0xsecond" {
+		t.Errorf("Expected only 0xsecond to be emitted again, got %s", second.Transaction.TxId)
+	}
+	var feed []string
+	for _, whale := range monitor.Feed() {
+		feed = append(feed, whale.Transaction.TxId)
+	}
+	if strings.Join(feed, ",") != "0xfirst,0xsecond" {
+		t.Errorf("Expected each transaction once in the feed, got %v", feed)
+	}
+}